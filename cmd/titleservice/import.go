@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TV4/mms/titleservice"
+	"github.com/TV4/mms/titleservice/bulk"
+)
+
+func runImport(fs *flag.FlagSet, cf *clientOptions, kind string, args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	var (
+		format  = fs.String("format", "", "file format csv or jsonl (default based on the file extension)")
+		comma   = fs.String("comma", ",", "CSV field delimiter")
+		workers = fs.Int("workers", 4, "number of requests sent concurrently")
+		dryRun  = fs.Bool("dry-run", false, "only read and validate the file")
+		columns = columnFlag{}
+	)
+
+	fs.Var(columns, "column", "map a CSV column header to a field, as header=json_tag (repeatable)")
+
+	switch bulk.Kind(kind) {
+	case bulk.Series, bulk.Episode, bulk.Clip:
+	default:
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() != 1 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	filename := fs.Arg(0)
+
+	f, err := os.Open(filename)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer f.Close()
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	}
+
+	delimiter := []rune(*comma)
+
+	if len(delimiter) != 1 {
+		fmt.Fprintf(stderr, "invalid delimiter %q\n", *comma)
+		return 2
+	}
+
+	var records []bulk.Record
+
+	switch *format {
+	case "csv":
+		records, err = bulk.ReadCSV(f, bulk.CSVOptions{
+			Kind:    bulk.Kind(kind),
+			Columns: columns,
+			Comma:   delimiter[0],
+		})
+	case "jsonl", "ndjson":
+		records, err = bulk.ReadJSONLines(f, bulk.Kind(kind))
+	default:
+		fmt.Fprintf(stderr, "unknown format %q\n", *format)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if *dryRun {
+		invalid := 0
+
+		for _, r := range records {
+			if r.Err != nil {
+				invalid++
+				fmt.Fprintln(stderr, r.Err)
+			}
+		}
+
+		fmt.Fprintf(stdout, "%d valid, %d invalid\n", len(records)-invalid, invalid)
+
+		if invalid > 0 {
+			return 1
+		}
+
+		return 0
+	}
+
+	c, err := cf.client(getenv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	report := bulk.Import(context.Background(), c, records, titleservice.Workers(*workers))
+
+	for _, r := range report.Failures() {
+		fmt.Fprintln(stderr, r.Err)
+	}
+
+	fmt.Fprintln(stdout, report)
+
+	if report.Failed > 0 || report.Conflicts > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// columnFlag is a flag.Value collecting header=json_tag mappings
+type columnFlag map[string]string
+
+func (f columnFlag) String() string {
+	var pairs []string
+
+	for header, tag := range f {
+		pairs = append(pairs, header+"="+tag)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func (f columnFlag) Set(s string) error {
+	i := strings.LastIndex(s, "=")
+	if i < 1 {
+		return fmt.Errorf("expected header=json_tag, got %q", s)
+	}
+
+	f[s[:i]] = s[i+1:]
+
+	return nil
+}
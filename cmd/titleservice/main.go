@@ -0,0 +1,355 @@
+/*
+
+Command titleservice registers, updates and validates titles using the MMS TitleService API
+
+Usage:
+
+	titleservice register series|episode|clip [flags]
+	titleservice update series|episode|clip [flags]
+	titleservice validate series|episode|clip [flags]
+	titleservice import series|episode|clip [flags] file
+
+The credentials are read from the environment variables TITLESERVICE_USER and
+TITLESERVICE_PASS, or from a file given using -credentials containing a single
+line in the format username:password.
+
+Register prints the response from the MMS TitleService API as JSON.
+Update replaces the content of an already registered title, and prints the response like register.
+Validate checks the title offline and prints the form body that would be sent.
+Import registers the titles in a CSV or JSON Lines file, and prints a summary.
+
+Run titleservice register episode -h to list the flags for an episode.
+
+*/
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/TV4/mms/titleservice"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, os.Getenv))
+}
+
+const usage = `usage:
+	titleservice register series|episode|clip [flags]
+	titleservice update series|episode|clip [flags]
+	titleservice validate series|episode|clip [flags]
+	titleservice import series|episode|clip [flags] file
+`
+
+func run(args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	if len(args) < 2 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	command, kind := args[0], args[1]
+
+	fs := flag.NewFlagSet(command+" "+kind, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	cf := clientFlags(fs)
+
+	if command == "import" {
+		return runImport(fs, cf, kind, args[2:], stdout, stderr, getenv)
+	}
+
+	var req titleservice.Request
+
+	switch kind {
+	case "series":
+		req = seriesFlags(fs)
+	case "episode":
+		req = episodeFlags(fs)
+	case "clip":
+		req = clipFlags(fs)
+	default:
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	if err := fs.Parse(args[2:]); err != nil {
+		return 2
+	}
+
+	if e, ok := req.(*titleservice.Episode); ok {
+		for _, name := range e.IgnoredFields() {
+			fmt.Fprintf(stderr, "Episode %s: ignored for category %d\n", name, e.CategoryID)
+		}
+	}
+
+	switch command {
+	case "validate":
+		params, err := req.Params()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+
+		fmt.Fprintln(stdout, params.Encode())
+
+		return 0
+	case "register", "update":
+		c, err := cf.client(getenv)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+
+		send := register
+
+		if command == "update" {
+			send = update
+		}
+
+		resp, err := send(context.Background(), c, req)
+
+		if resp != nil {
+			printJSON(stdout, resp)
+		}
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+
+		return 0
+	default:
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+}
+
+func register(ctx context.Context, c *titleservice.Client, req titleservice.Request) (*titleservice.Response, error) {
+	switch r := req.(type) {
+	case *titleservice.Series:
+		return c.RegisterSeries(ctx, *r)
+	case *titleservice.Episode:
+		return c.RegisterEpisode(ctx, *r)
+	case *titleservice.Clip:
+		return c.RegisterClip(ctx, *r)
+	}
+
+	return nil, fmt.Errorf("unsupported request %T", req)
+}
+
+func update(ctx context.Context, c *titleservice.Client, req titleservice.Request) (*titleservice.Response, error) {
+	switch r := req.(type) {
+	case *titleservice.Series:
+		return c.UpdateSeries(ctx, *r)
+	case *titleservice.Episode:
+		return c.UpdateEpisode(ctx, *r)
+	case *titleservice.Clip:
+		return c.UpdateClip(ctx, *r)
+	}
+
+	return nil, fmt.Errorf("unsupported request %T", req)
+}
+
+// clientOptions are the flags used to create a client
+type clientOptions struct {
+	credentials string
+	baseURL     string
+	simulate    bool
+}
+
+func clientFlags(fs *flag.FlagSet) *clientOptions {
+	o := &clientOptions{}
+
+	fs.StringVar(&o.credentials, "credentials", "", "file containing username:password")
+	fs.StringVar(&o.baseURL, "base-url", "", "base URL of the MMS TitleService API")
+	fs.BoolVar(&o.simulate, "simulate", false, "send simulated requests (nothing is saved by MMS)")
+
+	return o
+}
+
+func (o *clientOptions) client(getenv func(string) string) (*titleservice.Client, error) {
+	username, password, err := loadCredentials(o.credentials, getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []func(*titleservice.Client){
+		titleservice.Simulate(o.simulate),
+	}
+
+	if o.baseURL != "" {
+		options = append(options, titleservice.BaseURL(o.baseURL))
+	}
+
+	return titleservice.NewClient(username, password, options...), nil
+}
+
+// loadCredentials from the file if provided, otherwise from the environment
+func loadCredentials(filename string, getenv func(string) string) (string, string, error) {
+	if filename == "" {
+		return getenv("TITLESERVICE_USER"), getenv("TITLESERVICE_PASS"), nil
+	}
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", "", err
+	}
+
+	line := strings.TrimSpace(strings.SplitN(string(b), "\n", 2)[0])
+
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return "", "", errors.New(filename + ": expected username:password")
+	}
+
+	return line[:i], line[i+1:], nil
+}
+
+func seriesFlags(fs *flag.FlagSet) *titleservice.Series {
+	s := &titleservice.Series{}
+
+	fs.StringVar(&s.SeriesCode, "series-code", "", "series code (required)")
+	fs.StringVar(&s.Title, "title", "", "title (required)")
+	fs.IntVar(&s.SeasonNumber, "season-number", 0, "season number")
+	fs.StringVar(&s.Description, "description", "", "description")
+	fs.StringVar(&s.GenreText, "genre-text", "", "genre text")
+
+	return s
+}
+
+func episodeFlags(fs *flag.FlagSet) *titleservice.Episode {
+	e := &titleservice.Episode{}
+
+	fs.StringVar(&e.TitleCode, "title-code", "", "title code (required)")
+	fs.StringVar(&e.SeriesCode, "series-code", "", "series code (required)")
+	fs.StringVar(&e.Title, "title", "", "title (required)")
+	fs.IntVar(&e.Length, "length", 0, "length in seconds (required)")
+	fs.Var(textFlag{&e.PublishedAt}, "published-at", "publishing date YYYYMMDD (required)")
+	fs.Var(textFlag{&e.AvailableUntil}, "available-until", "last available date YYYYMMDD")
+	fs.Var(categoryFlag{&e.CategoryID}, "category-id", "category id 1-10 (required)")
+	fs.IntVar(&e.EpisodeNumber, "episode-number", 0, "episode number "+categories("EpisodeNumber"))
+	fs.StringVar(&e.Description, "description", "", "description")
+	fs.StringVar(&e.LinkedTitleCode, "linked-title-code", "", "linked title code "+categories("LinkedTitleCode"))
+	fs.StringVar(&e.LiveTitle, "live-title", "", "live title "+categories("LiveTitle"))
+	fs.Var(textFlag{&e.LiveTvDay}, "live-tv-day", "live tv day YYYYMMDD "+categories("LiveTvDay"))
+	fs.Var(textFlag{&e.LiveTime}, "live-time", "live time HHMM between 0200 and 2559 "+categories("LiveTime"))
+	fs.Var(channelFlag{&e.LiveChannelID}, "live-channel", "live channel id or name "+categories("LiveChannelID"))
+	fs.StringVar(&e.PlayURL, "play-url", "", "play URL")
+	fs.StringVar((*string)(&e.TargetGroupCode), "target-group", "", "target group code V (adults) or B (children)")
+	fs.StringVar((*string)(&e.TerritoryCode), "territory", "", "territory code S (Swedish) or U (foreign)")
+	fs.StringVar(&e.SuggestedGenre1, "genre1", "", "suggested genre")
+	fs.StringVar(&e.SuggestedGenre2, "genre2", "", "suggested genre")
+	fs.StringVar(&e.SuggestedGenre3, "genre3", "", "suggested genre")
+
+	return e
+}
+
+// categories returns the categories the named Episode field applies to, e.g. "(categories 1, 2, 4, 5)"
+func categories(name string) string {
+	return "(categories " + titleservice.CategoriesWith(name).String() + ")"
+}
+
+func clipFlags(fs *flag.FlagSet) *titleservice.Clip {
+	c := &titleservice.Clip{}
+
+	fs.StringVar(&c.TitleCode, "title-code", "", "title code (required)")
+	fs.StringVar(&c.Title, "title", "", "title (required)")
+	fs.IntVar(&c.Length, "length", 0, "length in seconds (required)")
+	fs.Var(textFlag{&c.PublishedAt}, "published-at", "publishing date YYYYMMDD (required)")
+	fs.Var(textFlag{&c.AvailableUntil}, "available-until", "last available date YYYYMMDD")
+	fs.StringVar(&c.Description, "description", "", "description")
+	fs.StringVar(&c.PlayURL, "play-url", "", "play URL")
+
+	return c
+}
+
+type text interface {
+	MarshalText() ([]byte, error)
+	UnmarshalText([]byte) error
+}
+
+// textFlag is a flag.Value for the MMSDate and MMSTime types
+type textFlag struct {
+	v text
+}
+
+func (f textFlag) String() string {
+	if f.v == nil {
+		return ""
+	}
+
+	b, _ := f.v.MarshalText()
+
+	return string(b)
+}
+
+func (f textFlag) Set(s string) error {
+	return f.v.UnmarshalText([]byte(s))
+}
+
+type categoryFlag struct {
+	id *titleservice.CategoryID
+}
+
+func (f categoryFlag) String() string {
+	if f.id == nil || *f.id == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(int(*f.id))
+}
+
+func (f categoryFlag) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+
+	*f.id = titleservice.CategoryID(n)
+
+	return nil
+}
+
+// channelFlag is a flag.Value accepting a LiveChannelID or a channel name
+type channelFlag struct {
+	id *titleservice.LiveChannelID
+}
+
+func (f channelFlag) String() string {
+	if f.id == nil || *f.id == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(int(*f.id))
+}
+
+func (f channelFlag) Set(s string) error {
+	if n, err := strconv.Atoi(s); err == nil {
+		*f.id = titleservice.LiveChannelID(n)
+		return nil
+	}
+
+	id, err := titleservice.ResolveLiveChannelID(s)
+	if err != nil {
+		return err
+	}
+
+	*f.id = id
+
+	return nil
+}
+
+func printJSON(w io.Writer, v interface{}) {
+	enc := json.NewEncoder(w)
+
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
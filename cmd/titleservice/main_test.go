@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TV4/mms/titleservice/titleservicetest"
+)
+
+func TestRun(t *testing.T) {
+	ts := titleservicetest.NewServer("user", "pass")
+	defer ts.Close()
+
+	env := map[string]string{
+		"TITLESERVICE_USER": "user",
+		"TITLESERVICE_PASS": "pass",
+	}
+
+	credentials := filepath.Join(t.TempDir(), "credentials")
+
+	if err := ioutil.WriteFile(credentials, []byte("user:pass\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{[]string{}, 2, "", "usage:"},
+		{[]string{"register", "movie"}, 2, "", "usage:"},
+		{[]string{"validate", "clip", "-title-code", "TC", "-title", "T", "-length", "10", "-published-at", "20170327"}, 0, "Length=10&PublishedAt=20170327&Title=T&TitleCode=TC", ""},
+		{[]string{"validate", "clip", "-title-code", "TC", "-length", "10"}, 1, "", "Clip Title: missing parameter; Clip PublishedAt: missing parameter"},
+		{[]string{"validate", "clip", "-published-at", "20170230"}, 2, "", `date "20170230" does not exist`},
+		{[]string{"validate", "episode", "-title-code", "TC", "-series-code", "SC", "-title", "T", "-length", "10", "-published-at", "20170327", "-category-id", "1",
+			"-live-title", "LT", "-live-tv-day", "20170326", "-live-time", "2515", "-live-channel", "TV4"}, 0, "LiveChannelID=1029", ""},
+		{[]string{"validate", "episode", "-title-code", "TC", "-series-code", "SC", "-title", "T", "-length", "10", "-published-at", "20170327", "-category-id", "4",
+			"-live-title", "LT"}, 0, "CategoryID=4", "Episode LiveTitle: ignored for category 4"},
+		{[]string{"validate", "episode", "-live-channel", "TV4 Komdi"}, 2, "", `unknown channel "TV4 Komdi" (did you mean "TV4 Komedi"?)`},
+		{[]string{"register", "series", "-base-url", ts.URL, "-series-code", "SC", "-title", "T"}, 0, `"StatusCode": 200`, ""},
+		{[]string{"register", "series", "-base-url", ts.URL, "-series-code", "SC", "-title", "T"}, 1, `"StatusCode": 409`, "already registered"},
+		{[]string{"register", "series", "-base-url", ts.URL, "-credentials", credentials, "-simulate", "-series-code", "SC2", "-title", "T"}, 0, `"StatusCode": 200`, ""},
+		{[]string{"update", "series", "-base-url", ts.URL, "-series-code", "SC", "-title", "T2"}, 0, `"StatusCode": 200`, ""},
+		{[]string{"update", "series", "-base-url", ts.URL, "-series-code", "SC3", "-title", "T"}, 1, `"StatusCode": 404`, "not registered"},
+	} {
+		var stdout, stderr bytes.Buffer
+
+		code := run(tt.args, &stdout, &stderr, func(key string) string { return env[key] })
+
+		if code != tt.code {
+			t.Fatalf("run(%q) = %d, want %d (stderr: %s)", tt.args, code, tt.code, stderr.String())
+		}
+
+		if !strings.Contains(stdout.String(), tt.stdout) {
+			t.Fatalf("run(%q) stdout = %q, want it to contain %q", tt.args, stdout.String(), tt.stdout)
+		}
+
+		if !strings.Contains(stderr.String(), tt.stderr) {
+			t.Fatalf("run(%q) stderr = %q, want it to contain %q", tt.args, stderr.String(), tt.stderr)
+		}
+	}
+
+	if got, want := len(ts.Series()), 1; got != want {
+		t.Fatalf("len(ts.Series()) = %d, want %d", got, want)
+	}
+}
+
+func TestRunImport(t *testing.T) {
+	ts := titleservicetest.NewServer("user", "pass")
+	defer ts.Close()
+
+	env := map[string]string{
+		"TITLESERVICE_USER": "user",
+		"TITLESERVICE_PASS": "pass",
+	}
+
+	dir := t.TempDir()
+
+	csvFile := filepath.Join(dir, "clips.csv")
+	upperCSVFile := filepath.Join(dir, "CLIPS.CSV")
+
+	for _, name := range []string{csvFile, upperCSVFile} {
+		if err := ioutil.WriteFile(name, []byte("Kod;Titel;length;published_at\nTC1;First;60;20170327\nTC2;;60;20170327\n"), 0600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	jsonlFile := filepath.Join(dir, "series.jsonl")
+
+	if err := ioutil.WriteFile(jsonlFile, []byte(`{"series_code":"SC","title":"Series"}`+"\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{[]string{"import", "clip", "-comma", ";", "-column", "Kod=title_code", "-column", "Titel=title", "-dry-run", csvFile}, 1, "1 valid, 1 invalid", "line 3: Clip Title: missing parameter"},
+		{[]string{"import", "clip", "-comma", ";", "-column", "Kod=title_code", "-column", "Titel=title", "-dry-run", upperCSVFile}, 1, "1 valid, 1 invalid", "line 3: Clip Title: missing parameter"},
+		{[]string{"import", "clip", "-base-url", ts.URL, "-comma", ";", "-column", "Kod=title_code", "-column", "Titel=title", csvFile}, 1, "1 registered, 0 conflicts, 1 failed", "line 3"},
+		{[]string{"import", "series", "-base-url", ts.URL, jsonlFile}, 0, "1 registered, 0 conflicts, 0 failed", ""},
+		{[]string{"import", "series", "-base-url", ts.URL, "-format", "jsonl", jsonlFile}, 1, "0 registered, 1 conflicts, 0 failed", "already registered"},
+		{[]string{"import", "series", "-format", "xml", jsonlFile}, 2, "", "unknown format"},
+	} {
+		var stdout, stderr bytes.Buffer
+
+		code := run(tt.args, &stdout, &stderr, func(key string) string { return env[key] })
+
+		if code != tt.code {
+			t.Fatalf("run(%q) = %d, want %d (stderr: %s)", tt.args, code, tt.code, stderr.String())
+		}
+
+		if !strings.Contains(stdout.String(), tt.stdout) {
+			t.Fatalf("run(%q) stdout = %q, want it to contain %q", tt.args, stdout.String(), tt.stdout)
+		}
+
+		if !strings.Contains(stderr.String(), tt.stderr) {
+			t.Fatalf("run(%q) stderr = %q, want it to contain %q", tt.args, stderr.String(), tt.stderr)
+		}
+	}
+}
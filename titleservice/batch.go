@@ -0,0 +1,85 @@
+package titleservice
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultBatchWorkers = 4
+
+// BatchResult is the outcome of registering a single Request in a batch
+type BatchResult struct {
+	Response *Response
+	Err      error
+}
+
+// BatchOptions used when registering a batch of requests
+type BatchOptions struct {
+	Workers int // number of requests sent concurrently
+}
+
+// Workers changes the number of requests sent concurrently in a batch
+func Workers(n int) func(*BatchOptions) {
+	return func(o *BatchOptions) {
+		o.Workers = n
+	}
+}
+
+// RegisterBatch registers any mix of Series, Episode and Clip requests using a pool of workers.
+//
+// The returned results are in the same order as the requests. If ctx is done before all
+// requests have been sent, the remaining results get the error from ctx.
+func (c *Client) RegisterBatch(ctx context.Context, requests []Request, options ...func(*BatchOptions)) []BatchResult {
+	o := &BatchOptions{
+		Workers: defaultBatchWorkers,
+	}
+
+	for _, f := range options {
+		f(o)
+	}
+
+	if o.Workers < 1 {
+		o.Workers = 1
+	}
+
+	results := make([]BatchResult, len(requests))
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < o.Workers && w < len(requests); w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				resp, err := c.register(ctx, requests[i])
+
+				results[i] = BatchResult{Response: resp, Err: err}
+			}
+		}()
+	}
+
+	i := 0
+
+send:
+	for ; i < len(requests); i++ {
+		select {
+		case <-ctx.Done():
+			break send
+		case indexes <- i:
+		}
+	}
+
+	close(indexes)
+
+	for ; i < len(requests); i++ {
+		results[i] = BatchResult{Err: ctx.Err()}
+	}
+
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,69 @@
+package titleservice
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRegisterBatch(t *testing.T) {
+	t.Run("mixed_requests", func(t *testing.T) {
+		ts, c := testServerAndClient(testUser, testPass, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/"+string(RegisterClipEndpoint) {
+				testHandlerFunc(http.StatusConflict, nil)(w, r)
+				return
+			}
+
+			testHandlerFunc(http.StatusOK, nil)(w, r)
+		})
+		defer ts.Close()
+
+		series := MakeSeries("series-code", "series-title")
+		clip := MakeClip("clip-title-code", "clip-title", 123, Date(2017, 3, 27))
+		episode := MakeEpisode("episode-title-code", "series-code", "episode-title", 123, Date(2017, 3, 27), Webisode)
+
+		results := c.RegisterBatch(context.Background(), []Request{&series, &clip, &episode, &Series{}}, Workers(2))
+
+		if got, want := len(results), 4; got != want {
+			t.Fatalf("len(results) = %d, want %d", got, want)
+		}
+
+		for i, want := range []error{nil, ErrAlreadyRegistered, nil, ErrMissingParameter} {
+			if got := ErrorCause(results[i].Err); got != want {
+				t.Fatalf("ErrorCause(results[%d].Err) = %v, want %v", i, got, want)
+			}
+		}
+
+		if got, want := results[0].Response.StatusCode, http.StatusOK; got != want {
+			t.Fatalf("results[0].Response.StatusCode = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		var calls int32
+
+		ts, c := testServerAndClient(testUser, testPass, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			testHandlerFunc(http.StatusOK, nil)(w, r)
+		})
+		defer ts.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		series := MakeSeries("series-code", "series-title")
+
+		results := c.RegisterBatch(ctx, []Request{&series, &series, &series})
+
+		for i, r := range results {
+			if r.Err == nil {
+				t.Fatalf("results[%d].Err = nil, want error", i)
+			}
+		}
+
+		if got := atomic.LoadInt32(&calls); got > 0 {
+			t.Fatalf("calls = %d, want 0", got)
+		}
+	})
+}
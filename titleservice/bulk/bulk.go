@@ -0,0 +1,184 @@
+/*
+
+Package bulk reads titles from CSV and JSON Lines files, and registers them using a titleservice.Client
+
+The fields of each title are named by the json tags of titleservice.Series,
+titleservice.Episode and titleservice.Clip, e.g. title_code and published_at.
+
+	records, err := bulk.ReadCSV(f, bulk.CSVOptions{
+		Kind: bulk.Episode,
+		Columns: map[string]string{
+			"Titelkod":   "title_code",
+			"Seriekod":   "series_code",
+			"Titel":      "title",
+			"Längd":      "length",
+			"Publicerad": "published_at",
+			"Kategori":   "category_id",
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report := bulk.Import(ctx, c, records)
+
+*/
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TV4/mms/titleservice"
+)
+
+// Kind of title in a file
+type Kind string
+
+// Kinds
+const (
+	Series  Kind = "series"
+	Episode Kind = "episode"
+	Clip    Kind = "clip"
+)
+
+// newRequest returns a new, empty request of the kind
+func (k Kind) newRequest() (titleservice.Request, error) {
+	switch k {
+	case Series:
+		return &titleservice.Series{}, nil
+	case Episode:
+		return &titleservice.Episode{}, nil
+	case Clip:
+		return &titleservice.Clip{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown kind %q", k)
+}
+
+// Record is a title read from a file
+type Record struct {
+	Line    int                  // line number in the file, starting at 1
+	Request titleservice.Request // *titleservice.Series, *titleservice.Episode or *titleservice.Clip
+	Err     error                // parse or validation error, if any
+}
+
+// LineError is an error for a line in a file
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the error for the line
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// validate sets the Err of the record to any validation error
+func (r *Record) validate() {
+	if r.Err != nil {
+		return
+	}
+
+	if v, ok := r.Request.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			r.Err = &LineError{Line: r.Line, Err: err}
+		}
+	}
+}
+
+// Outcome of registering a record
+type Outcome string
+
+// Outcomes
+const (
+	Registered Outcome = "registered"
+	Conflict   Outcome = "conflict"
+	Failed     Outcome = "failed"
+)
+
+// Result of registering a record
+type Result struct {
+	Record
+	Outcome  Outcome
+	Response *titleservice.Response
+}
+
+// Report summarises an import
+type Report struct {
+	Registered int
+	Conflicts  int
+	Failed     int
+	Results    []Result // in the same order as the records
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("%d registered, %d conflicts, %d failed", r.Registered, r.Conflicts, r.Failed)
+}
+
+// Failures returns the results that were not registered, including conflicts
+func (r Report) Failures() []Result {
+	var failures []Result
+
+	for _, res := range r.Results {
+		if res.Outcome != Registered {
+			failures = append(failures, res)
+		}
+	}
+
+	return failures
+}
+
+// Import registers every record without an error using c.RegisterBatch.
+// Records with parse or validation errors are reported as failed without being sent.
+func Import(ctx context.Context, c *titleservice.Client, records []Record, options ...func(*titleservice.BatchOptions)) Report {
+	report := Report{Results: make([]Result, len(records))}
+
+	var (
+		requests []titleservice.Request
+		indexes  []int
+	)
+
+	for i, r := range records {
+		report.Results[i] = Result{Record: r, Outcome: Failed}
+
+		if r.Err == nil {
+			requests = append(requests, r.Request)
+			indexes = append(indexes, i)
+		}
+	}
+
+	for j, br := range c.RegisterBatch(ctx, requests, options...) {
+		res := &report.Results[indexes[j]]
+
+		res.Response = br.Response
+
+		switch {
+		case br.Err == nil:
+			res.Outcome = Registered
+		case errors.Is(br.Err, titleservice.ErrAlreadyRegistered):
+			res.Outcome = Conflict
+			res.Err = &LineError{Line: res.Line, Err: br.Err}
+		default:
+			res.Err = &LineError{Line: res.Line, Err: br.Err}
+		}
+	}
+
+	for _, res := range report.Results {
+		switch res.Outcome {
+		case Registered:
+			report.Registered++
+		case Conflict:
+			report.Conflicts++
+		default:
+			report.Failed++
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,185 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/TV4/mms/titleservice"
+	"github.com/TV4/mms/titleservice/titleservicetest"
+)
+
+func TestReadCSV(t *testing.T) {
+	in := strings.Join([]string{
+		"Titelkod;series_code;Titel;length;Publicerad;category_id;",
+		"TC1;SC;First;60;20170327;4;x",
+		"TC2;SC;Second;sixty;20170327;4;x",
+		"TC3;SC;;60;20170230;4;x",
+		`TC4;SC;"Multi`,
+		`line";60;20170327;4;x`,
+		"",
+		"TC5;SC;Fifth;60;20170327;12;x",
+	}, "\n")
+
+	records, err := ReadCSV(strings.NewReader(in), CSVOptions{
+		Kind:  Episode,
+		Comma: ';',
+		Columns: map[string]string{
+			"titelkod":   "title_code",
+			"Titel":      "title",
+			"Publicerad": "published_at",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, tt := range []struct {
+		line int
+		err  string
+	}{
+		{2, ""},
+		{3, `line 3: length: "sixty" is not an integer`},
+		{4, `line 4: Publicerad: date "20170230" does not exist`},
+		{5, ""},
+		{8, "line 8: Episode CategoryID: invalid parameter"},
+	} {
+		r := records[i]
+
+		if got, want := r.Line, tt.line; got != want {
+			t.Fatalf("records[%d].Line = %d, want %d", i, got, want)
+		}
+
+		if got := errString(r.Err); !strings.Contains(got, tt.err) || (tt.err == "") != (got == "") {
+			t.Fatalf("records[%d].Err = %q, want %q", i, got, tt.err)
+		}
+	}
+
+	if got, want := len(records), 5; got != want {
+		t.Fatalf("len(records) = %d, want %d", got, want)
+	}
+
+	e := records[0].Request.(*titleservice.Episode)
+
+	want := titleservice.MakeEpisode("TC1", "SC", "First", 60, titleservice.Date(2017, 3, 27), titleservice.Webisode)
+
+	if *e != want {
+		t.Fatalf("records[0].Request = %+v, want %+v", *e, want)
+	}
+
+	if got, want := records[3].Request.(*titleservice.Episode).Title, "Multi\nline"; got != want {
+		t.Fatalf("records[3].Request.Title = %q, want %q", got, want)
+	}
+}
+
+func TestReadCSVUnknownColumns(t *testing.T) {
+	for _, tt := range []struct {
+		in      string
+		columns map[string]string
+		want    string
+	}{
+		{"title_code;title;Descripton;episode number\nTC1;First;D;1\n", nil, `unknown columns "Descripton", "episode number"`},
+		{"Kod;title\nTC1;First\n", map[string]string{"Kod": "titel_code"}, `unknown columns "Kod" (mapped to "titel_code")`},
+	} {
+		records, err := ReadCSV(strings.NewReader(tt.in), CSVOptions{Kind: Episode, Comma: ';', Columns: tt.columns})
+
+		if got := errString(err); got != tt.want {
+			t.Fatalf("ReadCSV(%q) err = %q, want %q", tt.in, got, tt.want)
+		}
+
+		if records != nil {
+			t.Fatalf("ReadCSV(%q) = %v, want no records", tt.in, records)
+		}
+	}
+}
+
+func TestReadJSONLines(t *testing.T) {
+	in := strings.Join([]string{
+		`{"title_code":"TC1","title":"First","length":60,"published_at":"20170327"}`,
+		``,
+		`{"title_code":"TC2","title":"Second","length":60,"published_at":"2017-03-27"}`,
+		`{"title_code":"TC3","title":"Third","length":60,"published_at":"20170327","unknown":1}`,
+		`{"title_code":"TC4","length":60,"published_at":"20170327"}`,
+	}, "\n")
+
+	records, err := ReadJSONLines(strings.NewReader(in), Clip)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, tt := range []struct {
+		line int
+		err  string
+	}{
+		{1, ""},
+		{3, `line 3: date "2017-03-27" is not in the format YYYYMMDD`},
+		{4, `line 4: json: unknown field "unknown"`},
+		{5, "line 5: Clip Title: missing parameter"},
+	} {
+		r := records[i]
+
+		if got, want := r.Line, tt.line; got != want {
+			t.Fatalf("records[%d].Line = %d, want %d", i, got, want)
+		}
+
+		if got := errString(r.Err); !strings.Contains(got, tt.err) || (tt.err == "") != (got == "") {
+			t.Fatalf("records[%d].Err = %q, want %q", i, got, tt.err)
+		}
+	}
+
+	if _, err := ReadJSONLines(strings.NewReader(in), Kind("movie")); err == nil {
+		t.Fatalf("expected error for unknown kind")
+	}
+}
+
+func TestImport(t *testing.T) {
+	ts := titleservicetest.NewServer("user", "pass")
+	defer ts.Close()
+
+	in := strings.Join([]string{
+		`{"series_code":"SC1","title":"First"}`,
+		`{"series_code":"SC2","title":"Second"}`,
+		`{"series_code":"SC1","title":"First again"}`,
+		`{"series_code":"SC3"}`,
+	}, "\n")
+
+	records, err := ReadJSONLines(strings.NewReader(in), Series)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := Import(context.Background(), ts.Client(), records, titleservice.Workers(1))
+
+	if got, want := report.String(), "2 registered, 1 conflicts, 1 failed"; got != want {
+		t.Fatalf("report.String() = %q, want %q", got, want)
+	}
+
+	failures := report.Failures()
+
+	if got, want := len(failures), 2; got != want {
+		t.Fatalf("len(failures) = %d, want %d", got, want)
+	}
+
+	if got, want := failures[0].Outcome, Conflict; got != want {
+		t.Fatalf("failures[0].Outcome = %q, want %q", got, want)
+	}
+
+	var lineErr *LineError
+
+	if !errors.As(failures[0].Err, &lineErr) || lineErr.Line != 3 {
+		t.Fatalf("failures[0].Err = %v, want a LineError for line 3", failures[0].Err)
+	}
+
+	if got, want := len(ts.Series()), 2; got != want {
+		t.Fatalf("len(ts.Series()) = %d, want %d", got, want)
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,223 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ReadJSONLines reads one title of the kind per line, as a JSON object using the
+// json tags of the title type. Empty lines are skipped.
+//
+// Lines that can't be parsed or validated get a Record with an error, while
+// the returned error is reserved for failures reading r.
+func ReadJSONLines(r io.Reader, kind Kind) ([]Record, error) {
+	if _, err := kind.newRequest(); err != nil {
+		return nil, err
+	}
+
+	var records []Record
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for line := 1; s.Scan(); line++ {
+		b := bytes.TrimSpace(s.Bytes())
+
+		if len(b) == 0 {
+			continue
+		}
+
+		req, _ := kind.newRequest()
+
+		rec := Record{Line: line, Request: req}
+
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+
+		if err := dec.Decode(req); err != nil {
+			rec.Err = &LineError{Line: line, Err: err}
+		}
+
+		rec.validate()
+
+		records = append(records, rec)
+	}
+
+	if err := s.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+// CSVOptions used when reading a CSV file
+type CSVOptions struct {
+	Kind    Kind              // kind of title on each row
+	Columns map[string]string // maps column headers to json tags, columns named by their json tag need no mapping
+	Comma   rune              // field delimiter, defaults to ','
+}
+
+// ReadCSV reads one title of the kind per row. The first row is a header naming the
+// columns, either by json tag or by a header mapped to a json tag in Columns.
+// Columns with an empty header are ignored, while any other header naming no
+// field of the kind is an error, before any row is read.
+//
+// Rows that can't be parsed or validated get a Record with an error, while
+// the returned error is reserved for failures reading r and unknown columns.
+func ReadCSV(r io.Reader, o CSVOptions) ([]Record, error) {
+	proto, err := o.Kind.newRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	cr := csv.NewReader(r)
+
+	if o.Comma != 0 {
+		cr.Comma = o.Comma
+	}
+
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]string{}
+
+	for h, tag := range o.Columns {
+		columns[normalizeHeader(h)] = tag
+	}
+
+	tags := make([]string, len(header))
+	known := jsonFields(proto)
+
+	var unknown []string
+
+	for i, h := range header {
+		h = normalizeHeader(strings.TrimPrefix(h, "\ufeff"))
+
+		tag, mapped := columns[h]
+		if !mapped {
+			tag = h
+		}
+
+		if _, ok := known[tag]; !ok && h != "" {
+			if mapped {
+				unknown = append(unknown, fmt.Sprintf("%q (mapped to %q)", header[i], tag))
+			} else {
+				unknown = append(unknown, fmt.Sprintf("%q", header[i]))
+			}
+		}
+
+		tags[i] = tag
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown columns %s", strings.Join(unknown, ", "))
+	}
+
+	var records []Record
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			if perr, ok := err.(*csv.ParseError); ok {
+				records = append(records, Record{Line: perr.StartLine, Err: &LineError{Line: perr.StartLine, Err: perr.Err}})
+				continue
+			}
+
+			return records, err
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		req, _ := o.Kind.newRequest()
+
+		rec := Record{Line: line, Request: req}
+
+		fields := jsonFields(req)
+
+		for i, value := range row {
+			if i >= len(tags) || strings.TrimSpace(value) == "" {
+				continue
+			}
+
+			f, ok := fields[tags[i]]
+			if !ok {
+				continue
+			}
+
+			if err := setField(f, strings.TrimSpace(value)); err != nil {
+				rec.Err = &LineError{Line: line, Err: fmt.Errorf("%s: %v", header[i], err)}
+				break
+			}
+		}
+
+		rec.validate()
+
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// jsonFields returns the fields of the struct pointed to by v, keyed by json tag
+func jsonFields(v interface{}) map[string]reflect.Value {
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+
+	fields := make(map[string]reflect.Value, rt.NumField())
+
+	for i := 0; i < rt.NumField(); i++ {
+		tag := strings.Split(rt.Field(i).Tag.Get("json"), ",")[0]
+
+		if tag != "" && tag != "-" {
+			fields[tag] = rv.Field(i)
+		}
+	}
+
+	return fields
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// setField parses s into the string, integer or encoding.TextUnmarshaler field f
+func setField(f reflect.Value, s string) error {
+	if f.Addr().Type().Implements(textUnmarshalerType) {
+		return f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", s)
+		}
+
+		f.SetInt(int64(n))
+	default:
+		return fmt.Errorf("unsupported field type %s", f.Type())
+	}
+
+	return nil
+}
+
+// normalizeHeader for case and whitespace insensitive matching of column headers
+func normalizeHeader(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,294 @@
+package titleservice
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FieldRule describes how a category specific field of an Episode applies to a category
+type FieldRule int
+
+// FieldRules
+const (
+	FieldIgnored  FieldRule = iota // not sent, and reported by IgnoredFields if set
+	FieldRejected                  // fails validation if set
+	FieldOptional                  // sent if set
+	FieldRequired                  // fails validation if not set, and is always sent
+)
+
+func (r FieldRule) String() string {
+	switch r {
+	case FieldIgnored:
+		return "ignored"
+	case FieldRejected:
+		return "rejected"
+	case FieldOptional:
+		return "optional"
+	case FieldRequired:
+		return "required"
+	}
+
+	return fmt.Sprintf("FieldRule(%d)", int(r))
+}
+
+// Applies returns true if the field is sent to the MMS TitleService API
+func (r FieldRule) Applies() bool {
+	return r >= FieldOptional
+}
+
+// categoryFieldNames are the category specific fields, in the order of Episode
+var categoryFieldNames = []string{
+	"EpisodeNumber",
+	"LinkedTitleCode",
+	"LiveTitle",
+	"LiveTvDay",
+	"LiveTime",
+	"LiveChannelID",
+}
+
+// CategoryRule lists how the category specific fields of an Episode apply to a category
+type CategoryRule struct {
+	CategoryID CategoryID
+	Name       string
+	Fields     map[string]FieldRule // keyed by the field names returned by CategoryFields
+}
+
+// Field returns the rule for the named field, FieldIgnored if it has none
+func (r CategoryRule) Field(name string) FieldRule {
+	return r.Fields[name]
+}
+
+// Applies returns true if the named field is sent for the category,
+// which is always the case for fields not returned by CategoryFields
+func (r CategoryRule) Applies(name string) bool {
+	if !slices.Contains(categoryFieldNames, name) {
+		return true
+	}
+
+	return r.Field(name).Applies()
+}
+
+// categoryRules is the rule table, indexed by CategoryID
+var categoryRules = [...]CategoryRule{
+	TvProgram: {TvProgram, "TV program", map[string]FieldRule{
+		"EpisodeNumber":   FieldOptional,
+		"LinkedTitleCode": FieldRejected,
+		"LiveTitle":       FieldRequired,
+		"LiveTvDay":       FieldRequired,
+		"LiveTime":        FieldRequired,
+		"LiveChannelID":   FieldRequired,
+	}},
+	TvSegment: {TvSegment, "TV segment", map[string]FieldRule{
+		"EpisodeNumber":   FieldOptional,
+		"LinkedTitleCode": FieldOptional,
+		"LiveTitle":       FieldRequired,
+		"LiveTvDay":       FieldRequired,
+		"LiveTime":        FieldRequired,
+		"LiveChannelID":   FieldRequired,
+	}},
+	TvExtra: {TvExtra, "TV extra", map[string]FieldRule{
+		"EpisodeNumber":   FieldRejected,
+		"LinkedTitleCode": FieldOptional,
+		"LiveTitle":       FieldRequired,
+		"LiveTvDay":       FieldRequired,
+		"LiveTime":        FieldRequired,
+		"LiveChannelID":   FieldRequired,
+	}},
+	Webisode: {Webisode, "Webisode", map[string]FieldRule{
+		"EpisodeNumber":   FieldOptional,
+		"LinkedTitleCode": FieldRejected,
+	}},
+	WebSegment: {WebSegment, "Web segment", map[string]FieldRule{
+		"EpisodeNumber":   FieldOptional,
+		"LinkedTitleCode": FieldRejected,
+	}},
+	WebExtra: {WebExtra, "Web extra", map[string]FieldRule{
+		"EpisodeNumber":   FieldRejected,
+		"LinkedTitleCode": FieldRejected,
+	}},
+	WebClip: {WebClip, "Web clip", map[string]FieldRule{
+		"EpisodeNumber":   FieldRejected,
+		"LinkedTitleCode": FieldRejected,
+	}},
+	Simulcast: {Simulcast, "Simulcast", map[string]FieldRule{
+		"EpisodeNumber":   FieldRejected,
+		"LinkedTitleCode": FieldOptional,
+		"LiveTitle":       FieldRequired,
+		"LiveTvDay":       FieldRequired,
+		"LiveTime":        FieldRequired,
+		"LiveChannelID":   FieldRequired,
+	}},
+	ChannelSimulcast: {ChannelSimulcast, "Channel simulcast", map[string]FieldRule{
+		"EpisodeNumber":   FieldRejected,
+		"LinkedTitleCode": FieldRejected,
+	}},
+	WebLiveBroadcast: {WebLiveBroadcast, "Web live broadcast", map[string]FieldRule{
+		"EpisodeNumber":   FieldRejected,
+		"LinkedTitleCode": FieldRejected,
+	}},
+}
+
+// CategoryFields returns the fields of an Episode with a rule per category,
+// every other field applies to all categories
+func CategoryFields() []string {
+	return slices.Clone(categoryFieldNames)
+}
+
+// CategoryRules returns the rules of every category, ordered by CategoryID
+func CategoryRules() []CategoryRule {
+	rules := make([]CategoryRule, 0, len(categoryRules)-1)
+
+	for _, r := range categoryRules[1:] {
+		rules = append(rules, r.clone())
+	}
+
+	return rules
+}
+
+// RuleFor returns the rule of the category, or false if id is not a valid CategoryID
+func RuleFor(id CategoryID) (CategoryRule, bool) {
+	if !validCategoryID(id) {
+		return CategoryRule{}, false
+	}
+
+	return categoryRules[id].clone(), true
+}
+
+// CategoryIDs is a list of categories
+type CategoryIDs []CategoryID
+
+// String returns the categories separated by commas, e.g. "1, 2, 4, 5"
+func (ids CategoryIDs) String() string {
+	s := make([]string, len(ids))
+
+	for i, id := range ids {
+		s[i] = strconv.Itoa(int(id))
+	}
+
+	return strings.Join(s, ", ")
+}
+
+// CategoriesWith returns the categories the named field applies to, ordered by CategoryID
+func CategoriesWith(name string) CategoryIDs {
+	var ids CategoryIDs
+
+	for _, r := range categoryRules[1:] {
+		if r.Applies(name) {
+			ids = append(ids, r.CategoryID)
+		}
+	}
+
+	return ids
+}
+
+func (r CategoryRule) clone() CategoryRule {
+	r.Fields = maps.Clone(r.Fields)
+
+	return r
+}
+
+// categoryField reads a category specific field of an Episode
+type categoryField struct {
+	isSet   func(*Episode) bool
+	value   func(*Episode) string
+	missing ValidationCode // reported if a required field is not set
+}
+
+var categoryFields = map[string]categoryField{
+	"EpisodeNumber": {
+		func(e *Episode) bool { return e.EpisodeNumber > 0 },
+		func(e *Episode) string { return fmt.Sprintf("%d", e.EpisodeNumber) },
+		FieldMissing,
+	},
+	"LinkedTitleCode": {
+		func(e *Episode) bool { return e.LinkedTitleCode != "" },
+		func(e *Episode) string { return e.LinkedTitleCode },
+		FieldMissing,
+	},
+	"LiveTitle": {
+		func(e *Episode) bool { return e.LiveTitle != "" },
+		func(e *Episode) string { return e.LiveTitle },
+		FieldMissing,
+	},
+	"LiveTvDay": {
+		func(e *Episode) bool { return !e.LiveTvDay.IsZero() },
+		func(e *Episode) string { return e.LiveTvDay.String() },
+		FieldMissing,
+	},
+	"LiveTime": {
+		func(e *Episode) bool { return !e.LiveTime.IsZero() },
+		func(e *Episode) string { return e.LiveTime.String() },
+		FieldMissing,
+	},
+	"LiveChannelID": {
+		func(e *Episode) bool { return e.LiveChannelID != 0 },
+		func(e *Episode) string { return fmt.Sprintf("%d", e.LiveChannelID) },
+		FieldBadFormat,
+	},
+}
+
+// IgnoredFields returns the category specific fields that are set, but
+// not sent to the MMS TitleService API for the category of the episode
+func (e *Episode) IgnoredFields() []string {
+	if !validCategoryID(e.CategoryID) {
+		return nil
+	}
+
+	r := categoryRules[e.CategoryID]
+
+	var ignored []string
+
+	for _, name := range categoryFieldNames {
+		if r.Field(name) == FieldIgnored && categoryFields[name].isSet(e) {
+			ignored = append(ignored, name)
+		}
+	}
+
+	return ignored
+}
+
+// validateCategory adds a FieldError for every category specific field breaking the rule of the category
+func (e *Episode) validateCategory(v *ValidationError) {
+	if !validCategoryID(e.CategoryID) {
+		return
+	}
+
+	r := categoryRules[e.CategoryID]
+
+	for _, name := range categoryFieldNames {
+		f := categoryFields[name]
+
+		switch r.Field(name) {
+		case FieldRejected:
+			if f.isSet(e) {
+				v.addf(name, FieldNotAllowed, "only applicable to categories "+CategoriesWith(name).String())
+			}
+		case FieldRequired:
+			if !f.isSet(e) {
+				v.add(name, f.missing)
+			}
+		}
+	}
+}
+
+// setCategoryParams sets the category specific params of the episode
+func (e *Episode) setCategoryParams(params url.Values) {
+	r := categoryRules[e.CategoryID]
+
+	for _, name := range categoryFieldNames {
+		f := categoryFields[name]
+
+		switch r.Field(name) {
+		case FieldRequired:
+			params.Set(name, f.value(e))
+		case FieldOptional:
+			if f.isSet(e) {
+				params.Set(name, f.value(e))
+			}
+		}
+	}
+}
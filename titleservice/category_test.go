@@ -0,0 +1,165 @@
+package titleservice
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCategoryRules(t *testing.T) {
+	rules := CategoryRules()
+
+	if got, want := len(rules), 10; got != want {
+		t.Fatalf("len(rules) = %d, want %d", got, want)
+	}
+
+	for i, r := range rules {
+		if got, want := r.CategoryID, CategoryID(i+1); got != want {
+			t.Fatalf("rules[%d].CategoryID = %d, want %d", i, got, want)
+		}
+
+		if r.Name == "" {
+			t.Fatalf("rules[%d].Name is empty", i)
+		}
+	}
+
+	rules[0].Fields["LiveTitle"] = FieldIgnored
+
+	if r, _ := RuleFor(TvProgram); r.Field("LiveTitle") != FieldRequired {
+		t.Fatalf("modifying the returned rules changed the rule table")
+	}
+
+	if _, ok := RuleFor(11); ok {
+		t.Fatalf("RuleFor(11) = _, true, want false")
+	}
+}
+
+func TestCategoryRuleApplies(t *testing.T) {
+	for _, tt := range []struct {
+		id    CategoryID
+		field string
+		rule  FieldRule
+		want  bool
+	}{
+		{TvProgram, "Title", FieldIgnored, true},
+		{TvProgram, "LiveTitle", FieldRequired, true},
+		{TvProgram, "LinkedTitleCode", FieldRejected, false},
+		{Webisode, "LiveTitle", FieldIgnored, false},
+		{ChannelSimulcast, "LiveChannelID", FieldIgnored, false},
+		{ChannelSimulcast, "LiveTitle", FieldIgnored, false},
+		{WebLiveBroadcast, "LiveChannelID", FieldIgnored, false},
+	} {
+		r, _ := RuleFor(tt.id)
+
+		if got := r.Field(tt.field); got != tt.rule {
+			t.Fatalf("RuleFor(%d).Field(%q) = %v, want %v", tt.id, tt.field, got, tt.rule)
+		}
+
+		if got := r.Applies(tt.field); got != tt.want {
+			t.Fatalf("RuleFor(%d).Applies(%q) = %v, want %v", tt.id, tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestCategoriesWith(t *testing.T) {
+	for _, tt := range []struct {
+		field string
+		want  string
+	}{
+		{"EpisodeNumber", "1, 2, 4, 5"},
+		{"LinkedTitleCode", "2, 3, 8"},
+		{"LiveTitle", "1, 2, 3, 8"},
+		{"LiveChannelID", "1, 2, 3, 8"},
+		{"Title", "1, 2, 3, 4, 5, 6, 7, 8, 9, 10"},
+	} {
+		if got := CategoriesWith(tt.field).String(); got != tt.want {
+			t.Fatalf("CategoriesWith(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestEpisodeIgnoredFields(t *testing.T) {
+	live := func(e *Episode) {
+		e.LiveTitle = "LT"
+		e.LiveTvDay = Date(2017, 3, 27)
+		e.LiveTime = BroadcastTime(20, 0)
+		e.LiveChannelID = TV4
+	}
+
+	for _, tt := range []struct {
+		e    Episode
+		want string
+	}{
+		{MakeEpisode("TC", "SC", "T", 1, Date(2017, 3, 27), TvProgram, live), "[]"},
+		{MakeEpisode("TC", "SC", "T", 1, Date(2017, 3, 27), Webisode, live), "[LiveTitle LiveTvDay LiveTime LiveChannelID]"},
+		{MakeEpisode("TC", "SC", "T", 1, Date(2017, 3, 27), ChannelSimulcast, live), "[LiveTitle LiveTvDay LiveTime LiveChannelID]"},
+		{MakeEpisode("TC", "SC", "T", 1, Date(2017, 3, 27), WebLiveBroadcast, live), "[LiveTitle LiveTvDay LiveTime LiveChannelID]"},
+		{MakeEpisode("TC", "SC", "T", 1, Date(2017, 3, 27), WebExtra), "[]"},
+	} {
+		if got := fmt.Sprint(tt.e.IgnoredFields()); got != tt.want {
+			t.Fatalf("IgnoredFields() for category %d = %s, want %s", tt.e.CategoryID, got, tt.want)
+		}
+	}
+}
+
+func TestEpisodeCategoryParams(t *testing.T) {
+	for _, tt := range []struct {
+		id      CategoryID
+		channel LiveChannelID
+		want    map[string]string
+	}{
+		{TvSegment, TV4, map[string]string{"EpisodeNumber": "3", "LinkedTitleCode": "LTC", "LiveTitle": "LT", "LiveChannelID": "1029"}},
+		{Webisode, 0, map[string]string{"EpisodeNumber": "3", "LinkedTitleCode": "", "LiveTitle": "", "LiveChannelID": ""}},
+		{ChannelSimulcast, TV4, map[string]string{"EpisodeNumber": "", "LiveTitle": "", "LiveChannelID": ""}},
+		{WebLiveBroadcast, TV4, map[string]string{"LiveTitle": "", "LiveChannelID": ""}},
+	} {
+		e := MakeEpisode("TC", "SC", "T", 1, Date(2017, 3, 27), tt.id, func(e *Episode) {
+			e.LiveTitle = "LT"
+			e.LiveTvDay = Date(2017, 3, 27)
+			e.LiveTime = BroadcastTime(20, 0)
+			e.LiveChannelID = tt.channel
+
+			if r, _ := RuleFor(tt.id); r.Applies("EpisodeNumber") {
+				e.EpisodeNumber = 3
+			}
+
+			if r, _ := RuleFor(tt.id); r.Applies("LinkedTitleCode") {
+				e.LinkedTitleCode = "LTC"
+			}
+		})
+
+		params, err := e.Params()
+		if err != nil {
+			t.Fatalf("unexpected error for category %d: %v", tt.id, err)
+		}
+
+		for key, want := range tt.want {
+			if got := params.Get(key); got != want {
+				t.Fatalf("params.Get(%q) for category %d = %q, want %q", key, tt.id, got, want)
+			}
+		}
+	}
+}
+
+func TestEpisodeValidateChannelSimulcast(t *testing.T) {
+	e := MakeEpisode("TC", "SC", "T", 1, Date(2017, 3, 27), ChannelSimulcast, func(e *Episode) {
+		e.LiveChannelID = 1
+		e.EpisodeNumber = 2
+	})
+
+	if got, want := fmt.Sprint(e.Validate()), "Episode EpisodeNumber: invalid parameter (only applicable to categories 1, 2, 4, 5)"; got != want {
+		t.Fatalf("e.Validate() = %q, want %q", got, want)
+	}
+
+	for _, id := range []CategoryID{ChannelSimulcast, WebLiveBroadcast} {
+		e.CategoryID = id
+		e.EpisodeNumber = 0
+
+		if err := e.Validate(); err != nil {
+			t.Fatalf("unexpected error for an ignored LiveChannelID in category %d: %v", id, err)
+		}
+
+		if got, want := fmt.Sprint(e.IgnoredFields()), "[LiveChannelID]"; got != want {
+			t.Fatalf("e.IgnoredFields() = %s, want %s", got, want)
+		}
+	}
+}
@@ -0,0 +1,431 @@
+package titleservice
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Channel is a Live TV broadcast channel
+type Channel struct {
+	ID        LiveChannelID `json:"id"`
+	Name      string        `json:"name"`              // as specified in the MMS TitleService documentation
+	Aliases   []string      `json:"aliases,omitempty"` // other names the channel is looked up by
+	ValidFrom MMSDate       `json:"valid_from"`        // the first broadcast day, or zero if unknown
+	ValidTo   MMSDate       `json:"valid_to"`          // the last broadcast day, or zero if still broadcasting
+}
+
+// ValidOn returns true if the channel is broadcasting on the broadcast day,
+// which is always the case for the zero MMSDate and for a channel without a
+// ValidFrom or ValidTo, as are all the built-in channels except Kanal 11
+func (ch Channel) ValidOn(day MMSDate) bool {
+	if day.IsZero() {
+		return true
+	}
+
+	if !ch.ValidFrom.IsZero() && day.Before(ch.ValidFrom) {
+		return false
+	}
+
+	return ch.ValidTo.IsZero() || !ch.ValidTo.Before(day)
+}
+
+// period describes the broadcast days of the channel, e.g. "from 20100101 to 20191231"
+func (ch Channel) period() string {
+	switch {
+	case ch.ValidFrom.IsZero() && ch.ValidTo.IsZero():
+		return "on every day"
+	case ch.ValidTo.IsZero():
+		return "from " + ch.ValidFrom.String()
+	case ch.ValidFrom.IsZero():
+		return "to " + ch.ValidTo.String()
+	}
+
+	return "from " + ch.ValidFrom.String() + " to " + ch.ValidTo.String()
+}
+
+// ChannelRegistry holds the channels accepted as LiveChannelID, and is safe for concurrent use
+type ChannelRegistry struct {
+	mu       sync.RWMutex
+	byID     map[LiveChannelID]Channel
+	byName   map[string]LiveChannelID
+	byNormal map[string]LiveChannelID // by normalizeChannelName, without names shared by several channels
+}
+
+// DefaultChannelRegistry is used by Episode.Validate, LookupLiveChannelID and LiveChannelID.String,
+// starting out with the channels in the MMS TitleService v1.2 documentation
+var DefaultChannelRegistry = NewChannelRegistry(builtinChannels...)
+
+// NewChannelRegistry creates a ChannelRegistry with the channels, panicking if they are
+// invalid (use Register to add channels read at runtime)
+func NewChannelRegistry(channels ...Channel) *ChannelRegistry {
+	r := &ChannelRegistry{}
+
+	if err := r.Replace(channels...); err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// Register adds the channels, replacing any registered channel with the same ID.
+// Nothing is registered if a channel is invalid, or a name is used by another channel.
+func (r *ChannelRegistry) Register(channels ...Channel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.register(r.byID, channels)
+}
+
+// Replace all registered channels with the channels, leaving the
+// registry unchanged if a channel is invalid or a name is used twice
+func (r *ChannelRegistry) Replace(channels ...Channel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.register(nil, channels)
+}
+
+// register channels on top of the existing ones, swapping in the new maps if all are valid
+func (r *ChannelRegistry) register(existing map[LiveChannelID]Channel, channels []Channel) error {
+	byID := make(map[LiveChannelID]Channel, len(existing)+len(channels))
+
+	for id, ch := range existing {
+		byID[id] = ch
+	}
+
+	for _, ch := range channels {
+		if ch.ID <= 0 {
+			return newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("channel %q has no valid id", ch.Name))
+		}
+
+		if ch.Name == "" {
+			return newErrorWithMessage(ErrMissingParameter, fmt.Sprintf("channel %d has no name", int(ch.ID)))
+		}
+
+		if !ch.ValidTo.IsZero() && ch.ValidTo.Before(ch.ValidFrom) {
+			return newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("channel %q valid to %s before valid from %s", ch.Name, ch.ValidTo, ch.ValidFrom))
+		}
+
+		ch.Aliases = slices.Clone(ch.Aliases)
+		byID[ch.ID] = ch
+	}
+
+	byName := make(map[string]LiveChannelID, len(byID))
+	byNormal := make(map[string]LiveChannelID, len(byID))
+	ambiguous := map[string]bool{}
+
+	for _, ch := range byID {
+		for _, name := range append([]string{ch.Name}, ch.Aliases...) {
+			if id, ok := byName[name]; ok && id != ch.ID {
+				return newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("channel name %q used by both %d and %d", name, int(id), int(ch.ID)))
+			}
+
+			byName[name] = ch.ID
+
+			key := normalizeChannelName(name)
+
+			if id, ok := byNormal[key]; ok && id != ch.ID {
+				ambiguous[key] = true
+			}
+
+			byNormal[key] = ch.ID
+		}
+	}
+
+	for key := range ambiguous {
+		delete(byNormal, key)
+	}
+
+	r.byID, r.byName, r.byNormal = byID, byName, byNormal
+
+	return nil
+}
+
+// Lookup the LiveChannelID of a channel by its name or one of its aliases. Names without
+// an exact match are compared ignoring case, whitespace, punctuation, diacritics and a
+// trailing "HD", also when written together with the name, e.g. "SVT1 HD", "TV4HD" and
+// "TV4 Fakta Xl" are found, unless several channels match or the name with its "HD" is the
+// start of another channel, e.g. "Discovery HD".
+func (r *ChannelRegistry) Lookup(name string) (LiveChannelID, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if id, ok := r.byName[name]; ok {
+		return id, true
+	}
+
+	key := normalizeChannelName(name)
+	if key == "" {
+		return 0, false
+	}
+
+	id, ok := r.byNormal[key]
+
+	if trimmed := strings.TrimSuffix(key, "hd"); !ok && trimmed != key && trimmed != "" {
+		key = trimmed
+		id, ok = r.byNormal[key]
+	}
+
+	if ok && r.abbreviates(name, key, id) {
+		return 0, false
+	}
+
+	return id, ok
+}
+
+// abbreviates reports whether name, normalized to key by dropping a trailing "HD",
+// is the start of the name of a channel other than id
+func (r *ChannelRegistry) abbreviates(name, key string, id LiveChannelID) bool {
+	full := strings.Join(channelNameWords(name), "")
+
+	if full == key {
+		return false
+	}
+
+	for other, otherID := range r.byName {
+		if otherID != id && strings.HasPrefix(strings.Join(channelNameWords(other), ""), full) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Channel returns the registered channel with the id
+func (r *ChannelRegistry) Channel(id LiveChannelID) (Channel, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ch, ok := r.byID[id]
+	ch.Aliases = slices.Clone(ch.Aliases)
+
+	return ch, ok
+}
+
+// Valid returns true if a channel with the id is registered
+func (r *ChannelRegistry) Valid(id LiveChannelID) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.byID[id]
+
+	return ok
+}
+
+// ValidOn returns true if a channel with the id is registered, and broadcasting on the broadcast day
+func (r *ChannelRegistry) ValidOn(id LiveChannelID, day MMSDate) bool {
+	ch, ok := r.Channel(id)
+
+	return ok && ch.ValidOn(day)
+}
+
+// Channels returns the registered channels, ordered by ID
+func (r *ChannelRegistry) Channels() []Channel {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	channels := make([]Channel, 0, len(r.byID))
+
+	for _, ch := range r.byID {
+		ch.Aliases = slices.Clone(ch.Aliases)
+		channels = append(channels, ch)
+	}
+
+	slices.SortFunc(channels, func(a, b Channel) int { return int(a.ID - b.ID) })
+
+	return channels
+}
+
+// ReadChannelsJSON reads a JSON array of channels, e.g.
+//
+//	[{"id": 1029, "name": "TV4", "aliases": ["TV4 HD"]}]
+func ReadChannelsJSON(r io.Reader) ([]Channel, error) {
+	var channels []Channel
+
+	if err := json.NewDecoder(r).Decode(&channels); err != nil {
+		return nil, newErrorWithMessage(ErrInvalidParameter, "channels: "+err.Error())
+	}
+
+	return channels, nil
+}
+
+// ReadChannelsCSV reads channels from CSV records with the id, the name and any aliases, e.g.
+//
+//	1029,TV4,TV4 HD
+//
+// A first record starting with "id" is a header naming the columns id, name,
+// valid_from and valid_to, with dates in the format YYYYMMDD, and any number
+// of alias or aliases columns. Other columns are an error, e.g.
+//
+//	id,name,valid_from,valid_to,alias
+//	9001,TV4 Hits,20180101,20191231,TV4 Hits HD
+func ReadChannelsCSV(r io.Reader) ([]Channel, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var (
+		channels []Channel
+		header   []string
+	)
+
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return channels, nil
+		}
+
+		if err != nil {
+			return nil, newErrorWithMessage(ErrInvalidParameter, "channels: "+err.Error())
+		}
+
+		if line == 1 && strings.EqualFold(record[0], "id") {
+			for _, column := range record {
+				column = strings.ToLower(strings.TrimSpace(column))
+
+				switch column {
+				case "id", "name", "valid_from", "valid_to", "alias", "aliases":
+				default:
+					return nil, newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("channels: line 1: unknown column %q", column))
+				}
+
+				header = append(header, column)
+			}
+
+			continue
+		}
+
+		ch, err := readChannelCSV(header, record)
+		if err != nil {
+			return nil, newErrorWithMessage(err, fmt.Sprintf("channels: line %d", line))
+		}
+
+		channels = append(channels, ch)
+	}
+}
+
+// readChannelCSV reads a channel from a record, with the columns named by header
+// if any, and otherwise the id and the name followed by aliases
+func readChannelCSV(header, record []string) (Channel, error) {
+	var (
+		ch  Channel
+		err error
+	)
+
+	for i, value := range record {
+		column := "alias"
+
+		switch {
+		case header != nil && i >= len(header):
+			if value != "" {
+				return Channel{}, newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("column %d is not in the header", i+1))
+			}
+
+			continue
+		case header != nil:
+			column = header[i]
+		case i == 0:
+			column = "id"
+		case i == 1:
+			column = "name"
+		}
+
+		switch column {
+		case "id":
+			id, aerr := strconv.Atoi(value)
+			if aerr != nil {
+				return Channel{}, newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("id %q is not a number", value))
+			}
+
+			ch.ID = LiveChannelID(id)
+		case "name":
+			ch.Name = value
+		case "valid_from":
+			err = ch.ValidFrom.UnmarshalText([]byte(value))
+		case "valid_to":
+			err = ch.ValidTo.UnmarshalText([]byte(value))
+		case "alias", "aliases":
+			if value != "" {
+				ch.Aliases = append(ch.Aliases, value)
+			}
+		}
+
+		if err != nil {
+			return Channel{}, err
+		}
+	}
+
+	if ch.Name == "" {
+		return Channel{}, newErrorWithMessage(ErrMissingParameter, "no name")
+	}
+
+	return ch, nil
+}
+
+// LoadChannels reads the channels in a .json or .csv file
+func LoadChannels(path string) ([]Channel, error) {
+	read := ReadChannelsCSV
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		read = ReadChannelsJSON
+	case ".csv":
+	default:
+		return nil, newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("channels: unsupported file extension %q", ext))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return read(f)
+}
+
+// builtinChannels from the MMS TitleService v1.2 documentation. Only Kanal 11 has a period,
+// starting at the beginning of its launch year so that no actual broadcast is rejected. The other
+// channels, Kanal 9 included, are valid on every day until registered again with ValidFrom and ValidTo.
+var builtinChannels = []Channel{
+	{ID: SVT1, Name: "svt1"},
+	{ID: SVT2, Name: "svt2"},
+	{ID: Discovery, Name: "Discovery"},
+	{ID: Kanal5, Name: "Kanal5"},
+	{ID: TV3, Name: "TV3"},
+	{ID: TV4, Name: "TV4"},
+	{ID: Kanal9, Name: "Kanal9"},
+	{ID: DiscoveryWorld, Name: "Discovery World"},
+	{ID: DiscoveryScience, Name: "Discovery Science"},
+	{ID: SVTB, Name: "svtB"},
+	{ID: TV3SportHD, Name: "TV3 Sport HD"},
+	{ID: EsportsTV, Name: "Esports TV"},
+	{ID: Sportkanalen, Name: "Sportkanalen"},
+	{ID: Sjuan, Name: "Sjuan"},
+	{ID: TV4Film, Name: "TV4 Film"},
+	{ID: TV6, Name: "TV6"},
+	{ID: TV4Sport, Name: "TV4 Sport"},
+	{ID: TV4Fakta, Name: "TV4 Fakta"},
+	{ID: TV4Guld, Name: "TV4 Guld"},
+	{ID: TV4Komedi, Name: "TV4 Komedi"},
+	{ID: TV8, Name: "TV8"},
+	{ID: AnimalPlanet, Name: "Animal Planet"},
+	{ID: SVT24, Name: "svt24"},
+	{ID: DiscoveryHDShowcase, Name: "Discovery HD Showcase"},
+	{ID: TV12, Name: "TV12"},
+	{ID: Kunskapskanalen, Name: "Kunskapskanalen"},
+	{ID: TV10, Name: "TV10"},
+	{ID: TLC, Name: "TLC"},
+	{ID: InvestigationDiscovery, Name: "Investigation Discovery"},
+	{ID: TV4FaktaXL, Name: "TV4 Fakta XL"},
+	{ID: Eurosport1, Name: "Eurosport 1"},
+	{ID: Kanal11, Name: "Kanal 11", ValidFrom: Date(2016, 1, 1)},
+	{ID: Eurosport2Sweden, Name: "Eurosport 2 Sweden"},
+}
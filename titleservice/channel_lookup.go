@@ -0,0 +1,166 @@
+package titleservice
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// UnknownChannelError is returned for a channel name that is not registered,
+// with the closest registered channels as suggestions
+type UnknownChannelError struct {
+	Name        string
+	Suggestions []Channel
+}
+
+func (e *UnknownChannelError) Error() string {
+	msg := fmt.Sprintf("unknown channel %q", e.Name)
+
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+
+	names := make([]string, len(e.Suggestions))
+
+	for i, ch := range e.Suggestions {
+		names[i] = fmt.Sprintf("%q", ch.Name)
+	}
+
+	return msg + " (did you mean " + strings.Join(names, ", ") + "?)"
+}
+
+// Unwrap returns ErrInvalidParameter
+func (e *UnknownChannelError) Unwrap() error {
+	return ErrInvalidParameter
+}
+
+// maxSuggestions is the number of suggestions in an UnknownChannelError
+const maxSuggestions = 3
+
+// Resolve the LiveChannelID of a channel name like Lookup, returning
+// an *UnknownChannelError with suggestions if there is no match
+func (r *ChannelRegistry) Resolve(name string) (LiveChannelID, error) {
+	if id, ok := r.Lookup(name); ok {
+		return id, nil
+	}
+
+	return 0, &UnknownChannelError{Name: name, Suggestions: r.Suggest(name, maxSuggestions)}
+}
+
+// ResolveLiveChannelID resolves a channel name using the DefaultChannelRegistry
+func ResolveLiveChannelID(name string) (LiveChannelID, error) {
+	return DefaultChannelRegistry.Resolve(name)
+}
+
+// Suggest returns up to n registered channels with a name or alias close to name, closest first
+func (r *ChannelRegistry) Suggest(name string, n int) []Channel {
+	query := normalizeChannelName(name)
+	if query == "" {
+		return nil
+	}
+
+	full := strings.Join(channelNameWords(name), "")
+
+	type match struct {
+		ch       Channel
+		distance int
+	}
+
+	var matches []match
+
+	for _, ch := range r.Channels() {
+		best := -1
+
+		for _, s := range append([]string{ch.Name}, ch.Aliases...) {
+			key := normalizeChannelName(s)
+			d := levenshtein(query, key)
+
+			// a prefix such as "eurosport" is as close as a single typo
+			if strings.HasPrefix(key, query) {
+				d = min(d, 1)
+			}
+
+			// and a channel starting with the name including its "HD" is the closest
+			if full != query && strings.HasPrefix(strings.Join(channelNameWords(s), ""), full) {
+				d = 0
+			}
+
+			if best < 0 || d < best {
+				best = d
+			}
+		}
+
+		if best <= max(2, len([]rune(query))/3) {
+			matches = append(matches, match{ch, best})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b match) int { return a.distance - b.distance })
+
+	suggestions := make([]Channel, 0, min(n, len(matches)))
+
+	for _, m := range matches[:min(n, len(matches))] {
+		suggestions = append(suggestions, m.ch)
+	}
+
+	return suggestions
+}
+
+// diacritics folded by normalizeChannelName
+var diacritics = strings.NewReplacer(
+	"å", "a", "ä", "a", "á", "a", "à", "a", "â", "a",
+	"ö", "o", "ø", "o", "ó", "o", "ò", "o", "ô", "o",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"ü", "u", "ú", "u", "ù", "u", "û", "u",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"æ", "ae", "ñ", "n", "ç", "c",
+)
+
+// normalizeChannelName returns the channel name in lower case, with diacritics folded,
+// without spaces and punctuation, and without a trailing "HD", e.g. "TV4 Fakta Xl" is "tv4faktaxl"
+func normalizeChannelName(name string) string {
+	words := channelNameWords(name)
+
+	if len(words) > 1 && words[len(words)-1] == "hd" {
+		words = words[:len(words)-1]
+	}
+
+	return strings.Join(words, "")
+}
+
+// channelNameWords returns the words of the channel name in lower case, with diacritics folded
+func channelNameWords(name string) []string {
+	return strings.FieldsFunc(diacritics.Replace(strings.ToLower(name)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(t)]
+}
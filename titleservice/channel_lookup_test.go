@@ -0,0 +1,139 @@
+package titleservice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeChannelName(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		want string
+	}{
+		{"svt1", "svt1"},
+		{"SVT1 HD", "svt1"},
+		{"TV4 Fakta Xl", "tv4faktaxl"},
+		{" Kanal  5 ", "kanal5"},
+		{"Kunskapskanalen", "kunskapskanalen"},
+		{"Kanal Ö-HD", "kanalo"},
+		{"Åre TV", "aretv"},
+		{"HD", "hd"},
+		{"", ""},
+	} {
+		if got := normalizeChannelName(tt.name); got != tt.want {
+			t.Fatalf("normalizeChannelName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLookupLiveChannelIDNormalized(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		id   LiveChannelID
+		ok   bool
+	}{
+		{"SVT1", SVT1, true},
+		{"SVT 1 HD", SVT1, true},
+		{"TV4 Fakta Xl", TV4FaktaXL, true},
+		{"Kanal 5", Kanal5, true},
+		{"kanal11", Kanal11, true},
+		{"TV3 Sport", TV3SportHD, true},
+		{"Discovery HD", 0, false},
+		{"TV4 HD", TV4, true},
+		{"SVT1HD", SVT1, true},
+		{"TV4HD", TV4, true},
+		{"TV3 SportHD", TV3SportHD, true},
+		{"DiscoveryHD", 0, false},
+		{"HD", 0, false},
+		{"TV4 Fkta", 0, false},
+		{"-", 0, false},
+	} {
+		id, ok := LookupLiveChannelID(tt.name)
+
+		if id != tt.id || ok != tt.ok {
+			t.Fatalf("LookupLiveChannelID(%q) = %d, %v, want %d, %v", tt.name, id, ok, tt.id, tt.ok)
+		}
+	}
+}
+
+func TestChannelRegistryAmbiguous(t *testing.T) {
+	r := NewChannelRegistry(Channel{ID: 1, Name: "Kanal Ett"}, Channel{ID: 2, Name: "kanal ett"})
+
+	if id, ok := r.Lookup("kanal ett"); !ok || id != 2 {
+		t.Fatalf("r.Lookup(%q) = %d, %v, want 2, true", "kanal ett", id, ok)
+	}
+
+	if _, ok := r.Lookup("KANAL ETT"); ok {
+		t.Fatalf("expected no match for a name normalized to several channels")
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		want string
+	}{
+		{"TV4 Fkta", "[TV4 Fakta]"},
+		{"TV4 Fakta X", "[TV4 Fakta TV4 Fakta XL]"},
+		{"Eurosport", "[Eurosport 1 Eurosport 2 Sweden]"},
+		{"Dicsovery World", "[Discovery World]"},
+		{"Kunskapskanaln", "[Kunskapskanalen]"},
+		{"BBC One", "[]"},
+		{"", "[]"},
+	} {
+		var names []string
+
+		for _, ch := range DefaultChannelRegistry.Suggest(tt.name, 2) {
+			names = append(names, ch.Name)
+		}
+
+		if got := fmt.Sprint(names); got != tt.want {
+			t.Fatalf("Suggest(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolveLiveChannelID(t *testing.T) {
+	if id, err := ResolveLiveChannelID("svt 2"); err != nil || id != SVT2 {
+		t.Fatalf("ResolveLiveChannelID(%q) = %d, %v, want %d, nil", "svt 2", id, err, SVT2)
+	}
+
+	_, err := ResolveLiveChannelID("TV4 Komdi")
+
+	var u *UnknownChannelError
+
+	if !errors.As(err, &u) || !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("err = %v, want *UnknownChannelError", err)
+	}
+
+	if got, want := err.Error(), `unknown channel "TV4 Komdi" (did you mean "TV4 Komedi"?)`; got != want {
+		t.Fatalf("err.Error() = %q, want %q", got, want)
+	}
+
+	if _, err := ResolveLiveChannelID("Discovery HD"); err == nil || !strings.Contains(err.Error(), `"Discovery HD Showcase"`) {
+		t.Fatalf("err = %v, want Discovery HD Showcase suggested", err)
+	}
+
+	if got, want := (&UnknownChannelError{Name: "BBC One"}).Error(), `unknown channel "BBC One"`; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"tv4", "", 3},
+		{"kitten", "sitting", 3},
+		{"tv4fakta", "tv4fkta", 1},
+		{"åre", "are", 1},
+	} {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Fatalf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package titleservice
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChannelRegistry(t *testing.T) {
+	r := NewChannelRegistry(Channel{ID: 1, Name: "One", Aliases: []string{"1st"}}, Channel{ID: 2, Name: "Two"})
+
+	for _, tt := range []struct {
+		name string
+		id   LiveChannelID
+		ok   bool
+	}{
+		{"One", 1, true},
+		{"1st", 1, true},
+		{"Two", 2, true},
+		{"Three", 0, false},
+	} {
+		if id, ok := r.Lookup(tt.name); id != tt.id || ok != tt.ok {
+			t.Fatalf("r.Lookup(%q) = %d, %v, want %d, %v", tt.name, id, ok, tt.id, tt.ok)
+		}
+	}
+
+	if err := r.Register(Channel{ID: 1, Name: "Uno"}, Channel{ID: 3, Name: "Three"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := r.Lookup("1st"); ok {
+		t.Fatalf("the aliases of a replaced channel should be removed")
+	}
+
+	if ch, ok := r.Channel(1); !ok || ch.Name != "Uno" {
+		t.Fatalf("r.Channel(1) = %+v, %v, want Uno", ch, ok)
+	}
+
+	if got, want := fmt.Sprint(r.Channels()), "[{1 Uno []  } {2 Two []  } {3 Three []  }]"; got != want {
+		t.Fatalf("r.Channels() = %s, want %s", got, want)
+	}
+
+	for _, tt := range []struct {
+		ch   Channel
+		want error
+	}{
+		{Channel{ID: 0, Name: "Zero"}, ErrInvalidParameter},
+		{Channel{ID: 4}, ErrMissingParameter},
+		{Channel{ID: 4, Name: "Four", Aliases: []string{"Two"}}, ErrInvalidParameter},
+	} {
+		if err := r.Register(tt.ch); !errors.Is(err, tt.want) {
+			t.Fatalf("r.Register(%+v) = %v, want %v", tt.ch, err, tt.want)
+		}
+	}
+
+	if r.Valid(4) {
+		t.Fatalf("r.Valid(4) = true after failing to register it")
+	}
+
+	if err := r.Replace(Channel{ID: 5, Name: "Five"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r.Valid(1) || !r.Valid(5) {
+		t.Fatalf("r.Replace should remove every other channel")
+	}
+}
+
+func TestDefaultChannelRegistry(t *testing.T) {
+	if got, want := len(DefaultChannelRegistry.Channels()), 33; got != want {
+		t.Fatalf("len(DefaultChannelRegistry.Channels()) = %d, want %d", got, want)
+	}
+
+	if id, ok := LookupLiveChannelID("SVT1"); !ok || id != SVT1 {
+		t.Fatalf("LookupLiveChannelID(%q) = %d, %v, want %d, true", "SVT1", id, ok, SVT1)
+	}
+
+	registerDefaultChannels(t, Channel{ID: 9001, Name: "TV4 Hits"})
+
+	if id, ok := LookupLiveChannelID("TV4 Hits"); !ok || id != 9001 {
+		t.Fatalf("LookupLiveChannelID(%q) = %d, %v, want 9001, true", "TV4 Hits", id, ok)
+	}
+
+	e := MakeEpisode("TC", "SC", "T", 1, Date(2017, 3, 27), TvProgram, WithLive("LT", Date(2017, 3, 27), BroadcastTime(20, 0), 9001))
+
+	if err := e.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLiveChannelIDString(t *testing.T) {
+	for _, tt := range []struct {
+		id   LiveChannelID
+		want string
+	}{
+		{SVT1, "svt1"},
+		{TV4, "TV4"},
+		{Kanal11, "Kanal 11"},
+		{LiveChannelID(9999), "9999"},
+	} {
+		if got := tt.id.String(); got != tt.want {
+			t.Fatalf("LiveChannelID(%d).String() = %q, want %q", int(tt.id), got, tt.want)
+		}
+	}
+}
+
+func TestReadChannels(t *testing.T) {
+	want := "[{9002 TV4 Nordic [TV4 Nordic HD]  } {9001 TV4 Hits [] 20180101 20191231}]"
+
+	fromJSON, err := ReadChannelsJSON(strings.NewReader(`[{"id": 9002, "name": "TV4 Nordic", "aliases": ["TV4 Nordic HD"]}, {"id": 9001, "name": "TV4 Hits", "valid_from": "20180101", "valid_to": "20191231"}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := fmt.Sprint(fromJSON); got != want {
+		t.Fatalf("ReadChannelsJSON = %s, want %s", got, want)
+	}
+
+	fromCSV, err := ReadChannelsCSV(strings.NewReader("ID,Name,Alias,valid_from,valid_to,aliases\n9002,TV4 Nordic,TV4 Nordic HD\n9001, TV4 Hits,,20180101,20191231,\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := fmt.Sprint(fromCSV); got != want {
+		t.Fatalf("ReadChannelsCSV = %s, want %s", got, want)
+	}
+
+	for _, s := range []string{"1029\n", "TV4,1029\n", "1029,\"TV4\n", "id,name,valid_to\n1029,TV4,20170230\n", "id,name,country\n1029,TV4,SE\n", "id,name\n1029,TV4,TV4 HD\n"} {
+		if _, err := ReadChannelsCSV(strings.NewReader(s)); err == nil {
+			t.Fatalf("ReadChannelsCSV(%q) returned no error", s)
+		}
+	}
+}
+
+func TestLoadChannels(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"channels.json": `[{"id": 9001, "name": "TV4 Hits"}]`,
+		"channels.csv":  "9001,TV4 Hits\n",
+		"channels.txt":  "9001 TV4 Hits\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, tt := range []struct {
+		name string
+		want string
+	}{
+		{"channels.json", "[{9001 TV4 Hits []  }]"},
+		{"channels.csv", "[{9001 TV4 Hits []  }]"},
+		{"channels.txt", "[]"},
+		{"missing.json", "[]"},
+	} {
+		channels, err := LoadChannels(filepath.Join(dir, tt.name))
+
+		if got := fmt.Sprint(channels); got != tt.want {
+			t.Fatalf("LoadChannels(%q) = %s (%v), want %s", tt.name, got, err, tt.want)
+		}
+
+		if (err != nil) != (tt.want == "[]") {
+			t.Fatalf("LoadChannels(%q) err = %v", tt.name, err)
+		}
+	}
+}
+
+func TestChannelValidOn(t *testing.T) {
+	ch := Channel{ID: 9001, Name: "TV4 Hits", ValidFrom: Date(2018, 1, 1), ValidTo: Date(2019, 12, 31)}
+
+	for _, tt := range []struct {
+		ch   Channel
+		day  MMSDate
+		want bool
+	}{
+		{ch, Date(2017, 12, 31), false},
+		{ch, Date(2018, 1, 1), true},
+		{ch, Date(2019, 12, 31), true},
+		{ch, Date(2020, 1, 1), false},
+		{ch, MMSDate{}, true},
+		{Channel{ValidFrom: Date(2018, 1, 1)}, Date(2030, 1, 1), true},
+		{Channel{ValidTo: Date(2018, 1, 1)}, Date(1990, 1, 1), true},
+		{Channel{}, Date(2018, 1, 1), true},
+	} {
+		if got := tt.ch.ValidOn(tt.day); got != tt.want {
+			t.Fatalf("%s.ValidOn(%s) = %v, want %v", tt.ch.period(), tt.day, got, tt.want)
+		}
+	}
+
+	r := NewChannelRegistry(ch)
+
+	if !r.ValidOn(9001, Date(2018, 6, 1)) || r.ValidOn(9001, Date(2020, 6, 1)) || r.ValidOn(9002, Date(2018, 6, 1)) {
+		t.Fatalf("r.ValidOn does not follow the validity period of the channel")
+	}
+
+	if err := r.Register(Channel{ID: 9002, Name: "TV4 Nordic", ValidFrom: Date(2019, 1, 1), ValidTo: Date(2018, 1, 1)}); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidParameter)
+	}
+}
+
+func TestEpisodeValidateBuiltinChannelPeriod(t *testing.T) {
+	for _, tt := range []struct {
+		day  MMSDate
+		want string
+	}{
+		{Date(2016, 1, 1), "<nil>"},
+		{Date(2015, 12, 31), "Episode LiveChannelID: invalid parameter (Kanal 11 not broadcasting on 20151231, only from 20160101)"},
+	} {
+		e := MakeEpisode("TC", "SC", "T", 1, Date(2016, 1, 1), TvProgram, WithLive("LT", tt.day, BroadcastTime(20, 0), Kanal11))
+
+		if got := fmt.Sprint(e.Validate()); got != tt.want {
+			t.Fatalf("e.Validate() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestEpisodeValidateChannelPeriod(t *testing.T) {
+	registerDefaultChannels(t, Channel{ID: 9001, Name: "TV4 Hits", ValidFrom: Date(2018, 1, 1), ValidTo: Date(2019, 12, 31)})
+
+	for _, tt := range []struct {
+		day  MMSDate
+		want string
+	}{
+		{Date(2018, 6, 1), "<nil>"},
+		{Date(2017, 6, 1), "Episode LiveChannelID: invalid parameter (TV4 Hits not broadcasting on 20170601, only from 20180101 to 20191231)"},
+		{Date(2020, 1, 1), "Episode LiveChannelID: invalid parameter (TV4 Hits not broadcasting on 20200101, only from 20180101 to 20191231)"},
+	} {
+		e := MakeEpisode("TC", "SC", "T", 1, Date(2020, 1, 1), TvProgram, WithLive("LT", tt.day, BroadcastTime(20, 0), 9001))
+
+		err := e.Validate()
+
+		if got := fmt.Sprint(err); got != tt.want {
+			t.Fatalf("e.Validate() = %q, want %q", got, tt.want)
+		}
+
+		var v *ValidationError
+
+		if errors.As(err, &v) && v.Field("LiveChannelID").Code != FieldNotOnAir {
+			t.Fatalf("code = %q, want %q", v.Field("LiveChannelID").Code, FieldNotOnAir)
+		}
+	}
+}
+
+// registerDefaultChannels registers the channels in the DefaultChannelRegistry until the test
+// has finished, so tests calling it must not run in parallel with other tests using the registry
+func registerDefaultChannels(t *testing.T, channels ...Channel) {
+	t.Helper()
+
+	if err := DefaultChannelRegistry.Register(channels...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := DefaultChannelRegistry.Replace(builtinChannels...); err != nil {
+			t.Errorf("unable to restore the DefaultChannelRegistry: %v", err)
+		}
+	})
+}
@@ -3,8 +3,10 @@ package titleservice
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
@@ -16,6 +18,7 @@ const (
 	defaultHost      = "titleservice.mms.se"
 	defaultUserAgent = "titleservice/client.go (godoc.org/github.com/TV4/mms/titleservice)"
 	defaultTimeout   = 30 * time.Second
+	maxErrorBodySize = 64 << 10
 )
 
 // Request interface used in requests to the MMS TitleService API
@@ -39,6 +42,14 @@ type Client struct {
 	username   string
 	password   string
 	simulate   bool
+	retry      RetryPolicy
+	limiter    *tokenBucket
+	hashes     HashStore
+	upsert     bool
+	middleware []Middleware
+	logger     *slog.Logger
+	metrics    Metrics
+	tracer     Tracer
 }
 
 // NewClient creates a MMS TitleService Client
@@ -54,6 +65,7 @@ func NewClient(username, password string, options ...func(*Client)) *Client {
 		userAgent: defaultUserAgent,
 		username:  username,
 		password:  password,
+		hashes:    NewMemoryHashStore(),
 	}
 
 	for _, f := range options {
@@ -93,6 +105,14 @@ func Simulate(b bool) func(c *Client) {
 	}
 }
 
+// Retry configures the client to retry requests that failed because of network errors,
+// timeouts or server errors (5xx) according to the provided RetryPolicy
+func Retry(policy RetryPolicy) func(*Client) {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
 // Simulated returns true if the client is configured to send simulated requests
 func (c *Client) Simulated() bool {
 	return c.simulate
@@ -119,16 +139,53 @@ func (c *Client) register(ctx context.Context, req Request) (*Response, error) {
 		return nil, newErrorWithMessage(err, string(req.Endpoint()))
 	}
 
-	return c.post(ctx, req.Endpoint(), params)
+	return c.send(ctx, c.newCall(req, params))
 }
 
 func (c *Client) post(ctx context.Context, endpoint Endpoint, params url.Values) (*Response, error) {
-	req, err := c.request(ctx, string(endpoint), params)
-	if err != nil {
-		return nil, err
-	}
+	for attempt := 1; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
 
-	return c.do(req)
+		req, err := c.request(ctx, string(endpoint), params)
+		if err != nil {
+			return nil, err
+		}
+
+		c.logRequest(ctx, endpoint, params, attempt)
+
+		start := time.Now()
+
+		resp, err := c.do(endpoint, req)
+
+		c.logResponse(ctx, endpoint, params, attempt, time.Since(start), resp, err)
+
+		if err == nil || c.retry == nil || !retryable(ctx, err) {
+			return resp, err
+		}
+
+		delay, ok := c.retry.Backoff(endpoint, attempt)
+		if !ok {
+			return resp, err
+		}
+
+		var apiErr *APIError
+
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+			delay = apiErr.RetryAfter
+		}
+
+		if !sleep(ctx, delay) {
+			return resp, err
+		}
+
+		if c.metrics != nil {
+			c.metrics.RequestRetried(endpoint)
+		}
+	}
 }
 
 func (c *Client) request(ctx context.Context, path string, params url.Values) (*http.Request, error) {
@@ -136,12 +193,9 @@ func (c *Client) request(ctx context.Context, path string, params url.Values) (*
 		return nil, err
 	}
 
-	params.Set("user", c.username)
-	params.Set("pass", c.password)
+	form := c.form(params)
 
-	if c.simulate {
-		params.Set("simulate", "")
-	}
+	form.Set("pass", c.password)
 
 	rel, err := url.Parse(path)
 	if err != nil {
@@ -150,7 +204,7 @@ func (c *Client) request(ctx context.Context, path string, params url.Values) (*
 
 	rawurl := c.baseURL.ResolveReference(rel).String()
 
-	req, err := http.NewRequest("POST", rawurl, strings.NewReader(params.Encode()))
+	req, err := http.NewRequest("POST", rawurl, strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, newErrorWithMessage(err, "unable to create POST request")
 	}
@@ -161,14 +215,49 @@ func (c *Client) request(ctx context.Context, path string, params url.Values) (*
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Add("User-Agent", c.userAgent)
 
+	if c.tracer != nil {
+		c.tracer.Inject(ctx, req.Header)
+	}
+
 	return req, nil
 }
 
-func (c *Client) do(req *http.Request) (*Response, error) {
+// form returns a copy of params with the username and simulate flag added, but not the password
+func (c *Client) form(params url.Values) url.Values {
+	form := make(url.Values, len(params)+3)
+
+	for key, values := range params {
+		form[key] = values
+	}
+
+	form.Set("user", c.username)
+
+	if c.simulate {
+		form.Set("simulate", "")
+	}
+
+	return form
+}
+
+func (c *Client) do(endpoint Endpoint, req *http.Request) (_ *Response, err error) {
+	if c.metrics != nil {
+		start := time.Now()
+
+		c.metrics.RequestStarted(endpoint)
+
+		defer func() {
+			c.metrics.RequestFinished(endpoint, outcome(err), time.Since(start))
+		}()
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, newErrorWithMessage(err, "error sending the request")
 	}
+
+	if span := spanFromContext(req.Context()); span != nil {
+		span.SetStatusCode(resp.StatusCode)
+	}
 	defer func() {
 		_, _ = io.CopyN(ioutil.Discard, resp.Body, 64)
 		_ = resp.Body.Close()
@@ -176,17 +265,28 @@ func (c *Client) do(req *http.Request) (*Response, error) {
 
 	switch resp.StatusCode {
 	case http.StatusBadRequest:
-		return errorResponse(resp, ErrInvalidInputData)
+		return errorResponse(endpoint, resp, ErrInvalidInputData)
 	case http.StatusForbidden:
-		return errorResponse(resp, ErrAuthenticationFailure)
+		return errorResponse(endpoint, resp, ErrAuthenticationFailure)
+	case http.StatusNotFound:
+		switch endpoint {
+		case UpdateSeriesEndpoint, UpdateEpisodeEndpoint, UpdateClipEndpoint:
+			return errorResponse(endpoint, resp, ErrNotRegistered)
+		}
+
+		return errorResponse(endpoint, resp, ErrNotFound)
 	case http.StatusConflict:
-		return errorResponse(resp, ErrAlreadyRegistered)
+		return errorResponse(endpoint, resp, ErrAlreadyRegistered)
+	case http.StatusTooManyRequests:
+		return errorResponse(endpoint, resp, ErrTooManyRequests)
 	case http.StatusInternalServerError:
-		return errorResponse(resp, ErrInternalServerError)
+		return errorResponse(endpoint, resp, ErrInternalServerError)
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return errorResponse(endpoint, resp, ErrServiceUnavailable)
 	}
 
 	if ct := resp.Header.Get("Content-Type"); !strings.Contains(ct, "application/json") {
-		return errorResponse(resp, newErrorWithMessage(ErrUnexpectedContentType, ct))
+		return errorResponse(endpoint, resp, newErrorWithMessage(ErrUnexpectedContentType, ct))
 	}
 
 	var r Response
@@ -198,12 +298,41 @@ func (c *Client) do(req *http.Request) (*Response, error) {
 	return &r, nil
 }
 
-func errorResponse(resp *http.Response, err error) (*Response, error) {
-	return &Response{
+// errorResponse returns a Response and an *APIError based on resp, using the
+// StatusDescription and Errors in the response body when it can be decoded
+func errorResponse(endpoint Endpoint, resp *http.Response, err error) (*Response, error) {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Endpoint:   endpoint,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		err:        err,
+	}
+
+	if ct := resp.Header.Get("Content-Type"); strings.Contains(ct, "application/json") {
+		var r Response
+
+		if json.NewDecoder(io.LimitReader(resp.Body, maxErrorBodySize)).Decode(&r) == nil {
+			apiErr.StatusDescription = r.StatusDescription
+			apiErr.Errors = r.Errors
+		}
+	}
+
+	r := &Response{
 		StatusCode:        resp.StatusCode,
-		StatusDescription: resp.Status,
-		Errors:            []string{err.Error()},
-	}, err
+		StatusDescription: apiErr.StatusDescription,
+		Errors:            apiErr.Errors,
+	}
+
+	if r.StatusDescription == "" {
+		r.StatusDescription = resp.Status
+	}
+
+	if len(r.Errors) == 0 {
+		r.Errors = []string{err.Error()}
+	}
+
+	return r, apiErr
 }
 
 func (c *Client) validateCredentials() error {
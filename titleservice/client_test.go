@@ -3,6 +3,8 @@ package titleservice
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -170,6 +172,96 @@ func TestClientRequest(t *testing.T) {
 	})
 }
 
+func TestClientErrors(t *testing.T) {
+	series := MakeSeries("series-code", "series-title")
+
+	for _, tt := range []struct {
+		name    string
+		hf      http.HandlerFunc
+		client  func(c *Client)
+		request Request
+		err     error
+	}{
+		{"invalid_parameters", testHandlerFunc(http.StatusOK, nil), nil, &Series{}, ErrMissingParameter},
+		{"no_username", testHandlerFunc(http.StatusOK, nil), func(c *Client) { c.username = "" }, &series, ErrNoUsername},
+		{"no_password", testHandlerFunc(http.StatusOK, nil), func(c *Client) { c.password = "" }, &series, ErrNoPassword},
+		{"bad_request", testHandlerFunc(http.StatusBadRequest, nil), nil, &series, ErrInvalidInputData},
+		{"forbidden", testHandlerFunc(http.StatusForbidden, nil), nil, &series, ErrAuthenticationFailure},
+		{"not_found", testHandlerFunc(http.StatusNotFound, nil), nil, &series, ErrNotFound},
+		{"not_registered", testHandlerFunc(http.StatusNotFound, nil), nil, update{&series, UpdateSeriesEndpoint}, ErrNotRegistered},
+		{"conflict", testHandlerFunc(http.StatusConflict, nil), nil, &series, ErrAlreadyRegistered},
+		{"too_many_requests", testHandlerFunc(http.StatusTooManyRequests, nil), nil, &series, ErrTooManyRequests},
+		{"internal_server_error", testHandlerFunc(http.StatusInternalServerError, nil), nil, &series, ErrInternalServerError},
+		{"bad_gateway", testHandlerFunc(http.StatusBadGateway, nil), nil, &series, ErrServiceUnavailable},
+		{"service_unavailable", testHandlerFunc(http.StatusServiceUnavailable, nil), nil, &series, ErrServiceUnavailable},
+		{"gateway_timeout", testHandlerFunc(http.StatusGatewayTimeout, nil), nil, &series, ErrServiceUnavailable},
+		{"unexpected_content_type", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+		}, nil, &series, ErrUnexpectedContentType},
+		{"invalid_json", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte("{"))
+		}, nil, &series, io.ErrUnexpectedEOF},
+		{"canceled", testHandlerFunc(http.StatusOK, nil), func(c *Client) {
+			c.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				return nil, context.Canceled
+			})}
+		}, &series, context.Canceled},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, c := testServerAndClient(testUser, testPass, tt.hf)
+			defer ts.Close()
+
+			if tt.client != nil {
+				tt.client(c)
+			}
+
+			_, err := c.register(context.Background(), tt.request)
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("errors.Is(%v, %v) = false, want true", err, tt.err)
+			}
+
+			if got, want := ErrorCause(err), tt.err; got != want {
+				t.Fatalf("ErrorCause(err) = %v, want %v", got, want)
+			}
+		})
+	}
+
+	t.Run("register_episode_not_found", func(t *testing.T) {
+		ts, c := testServerAndClient(testUser, testPass, testHandlerFunc(http.StatusNotFound, nil))
+		defer ts.Close()
+
+		_, err := c.RegisterEpisode(context.Background(), MakeEpisode("TC", "SC", "T", 1, Date(2017, 3, 27), Webisode))
+
+		var apiErr *APIError
+
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Endpoint != RegisterEpisodeEndpoint {
+			t.Fatalf("err = %v, want an *APIError with status 404 from %s", err, RegisterEpisodeEndpoint)
+		}
+
+		if errors.Is(err, ErrNotRegistered) || !errors.Is(err, ErrNotFound) {
+			t.Fatalf("err = %v, want %v", err, ErrNotFound)
+		}
+	})
+
+	t.Run("unable_to_parse_path", func(t *testing.T) {
+		_, err := testClient().post(context.Background(), Endpoint(":"), url.Values{})
+
+		var urlErr *url.Error
+
+		if !errors.As(err, &urlErr) {
+			t.Fatalf("errors.As(%v, &urlErr) = false, want true", err)
+		}
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
 const (
 	testUser = "testUser-123"
 	testPass = "testPass-XYZ"
@@ -7,7 +7,7 @@ import (
 )
 
 // MakeClip creates a Clip based on required parameters and optional parameters using options
-func MakeClip(titleCode, title string, length int, publishedAt string, options ...func(*Clip)) Clip {
+func MakeClip(titleCode, title string, length int, publishedAt MMSDate, options ...func(*Clip)) Clip {
 	c := &Clip{
 		TitleCode:   titleCode,
 		Title:       title,
@@ -24,13 +24,13 @@ func MakeClip(titleCode, title string, length int, publishedAt string, options .
 
 // Clip is a “stand-alone” short clip not linked to a series/season or other title
 type Clip struct {
-	TitleCode      string `json:"title_code"`                // required
-	Title          string `json:"title"`                     // required
-	Length         int    `json:"length"`                    // required
-	PublishedAt    string `json:"published_at"`              // required
-	AvailableUntil string `json:"available_until,omitempty"` // optional
-	Description    string `json:"description,omitempty"`     // optional
-	PlayURL        string `json:"play_url,omitempty"`        // optional
+	TitleCode      string  `json:"title_code"`            // required
+	Title          string  `json:"title"`                 // required
+	Length         int     `json:"length"`                // required
+	PublishedAt    MMSDate `json:"published_at"`          // required
+	AvailableUntil MMSDate `json:"available_until"`       // optional, not before PublishedAt
+	Description    string  `json:"description,omitempty"` // optional
+	PlayURL        string  `json:"play_url,omitempty"`    // optional, maximum of 150 characters
 }
 
 // Endpoint returns the endpoint to use for this request type
@@ -48,47 +48,61 @@ func (c *Clip) Params() (url.Values, error) {
 		"TitleCode":   {c.TitleCode},
 		"Title":       {c.Title},
 		"Length":      {fmt.Sprintf("%d", c.Length)},
-		"PublishedAt": {c.PublishedAt},
+		"PublishedAt": {c.PublishedAt.String()},
 	}
 
 	// optional parameters
 
-	if len(c.AvailableUntil) == 8 {
-		params.Set("AvailableUntil", c.AvailableUntil)
+	if !c.AvailableUntil.IsZero() {
+		params.Set("AvailableUntil", c.AvailableUntil.String())
 	}
 
 	if c.Description != "" {
 		params.Set("Description", c.Description)
 	}
 
-	if strings.HasPrefix(c.PlayURL, "http") {
+	if c.PlayURL != "" {
 		params.Set("PlayUrl", c.PlayURL)
 	}
 
 	return params, nil
 }
 
-// Validate all fields
+// Validate all fields, returning a *ValidationError listing every invalid field
 func (c *Clip) Validate() error {
+	v := &ValidationError{Type: "Clip"}
+
 	if c.TitleCode == "" {
-		return newErrorWithMessage(ErrMissingParameter, "Clip TitleCode")
+		v.add("TitleCode", FieldMissing)
 	}
 
 	if c.Title == "" {
-		return newErrorWithMessage(ErrMissingParameter, "Clip Title")
+		v.add("Title", FieldMissing)
 	}
 
 	if c.Length < 1 {
-		return newErrorWithMessage(ErrMissingParameter, "Clip Length")
+		v.add("Length", FieldMissing)
+	}
+
+	if c.PublishedAt.IsZero() {
+		v.add("PublishedAt", FieldMissing)
 	}
 
-	if len(c.PublishedAt) != 8 {
-		return newErrorWithMessage(ErrInvalidParameter, "Clip PublishedAt")
+	if !c.AvailableUntil.IsZero() && c.AvailableUntil.Before(c.PublishedAt) {
+		v.addf("AvailableUntil", FieldBadFormat, "before PublishedAt")
 	}
 
 	if strings.ContainsAny(c.Description, "<>") {
-		return newErrorWithMessage(ErrInvalidParameter, "Clip Description")
+		v.add("Description", FieldBadFormat)
+	}
+
+	if c.PlayURL != "" {
+		if !validPlayURL(c.PlayURL) {
+			v.addf("PlayURL", FieldBadFormat, "absolute http or https URL")
+		}
+
+		v.maxLength("PlayURL", c.PlayURL, maxPlayURLLength)
 	}
 
-	return nil
+	return v.err()
 }
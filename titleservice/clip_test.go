@@ -36,13 +36,15 @@ func TestClipValidate(t *testing.T) {
 		c    *Clip
 		want string
 	}{
-		{&Clip{}, "Clip TitleCode: missing parameter"},
-		{&Clip{TitleCode: "TC"}, "Clip Title: missing parameter"},
-		{&Clip{TitleCode: "TC", Title: "T"}, "Clip Length: missing parameter"},
-		{&Clip{TitleCode: "TC", Title: "T", Length: 1}, "Clip PublishedAt: invalid parameter"},
-		{&Clip{TitleCode: "TC", Title: "T", Length: 1, PublishedAt: "invalid"}, "Clip PublishedAt: invalid parameter"},
-		{&Clip{TitleCode: "TC", Title: "T", Length: 1, PublishedAt: "20070102"}, "<nil>"},
-		{&Clip{TitleCode: "TC", Title: "T", Length: 1, PublishedAt: "20070102", Description: "<b>"}, "Clip Description: invalid parameter"},
+		{&Clip{}, "Clip TitleCode: missing parameter; Clip Title: missing parameter; Clip Length: missing parameter; Clip PublishedAt: missing parameter"},
+		{&Clip{TitleCode: "TC"}, "Clip Title: missing parameter; Clip Length: missing parameter; Clip PublishedAt: missing parameter"},
+		{&Clip{TitleCode: "TC", Title: "T", PublishedAt: Date(2007, 1, 2)}, "Clip Length: missing parameter"},
+		{&Clip{TitleCode: "TC", Title: "T", Length: 1}, "Clip PublishedAt: missing parameter"},
+		{&Clip{TitleCode: "TC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2)}, "<nil>"},
+		{&Clip{TitleCode: "TC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), Description: "<b>"}, "Clip Description: invalid parameter"},
+		{&Clip{TitleCode: "TC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), AvailableUntil: Date(2006, 12, 31)}, "Clip AvailableUntil: invalid parameter (before PublishedAt)"},
+		{&Clip{TitleCode: "TC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), PlayURL: "ftp://example.com/clip"}, "Clip PlayURL: invalid parameter (absolute http or https URL)"},
+		{&Clip{TitleCode: "TC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), PlayURL: "http://example.com/clip"}, "<nil>"},
 	} {
 		if got := fmt.Sprintf("%v", tt.c.Validate()); got != tt.want {
 			t.Fatalf("tt.c.Validate() = %q, want %q", got, tt.want)
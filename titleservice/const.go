@@ -1,11 +1,23 @@
 package titleservice
 
-// Codes used in the MMS TitleService API
+import "strconv"
+
+// TargetGroup code of an Episode
+type TargetGroup string
+
+// TargetGroups
+const (
+	Adults   TargetGroup = "V" // Vuxen
+	Children TargetGroup = "B" // Barn
+)
+
+// Territory code of an Episode
+type Territory string
+
+// Territories
 const (
-	Adults   = "V"
-	Children = "B"
-	Swedish  = "S"
-	Foreign  = "U"
+	Swedish Territory = "S" // Svenskt
+	Foreign Territory = "U" // Utländskt
 )
 
 // Endpoint type
@@ -16,6 +28,9 @@ const (
 	RegisterSeriesEndpoint  Endpoint = "RegisterSeries"
 	RegisterEpisodeEndpoint Endpoint = "RegisterEpisode"
 	RegisterClipEndpoint    Endpoint = "RegisterClip"
+	UpdateSeriesEndpoint    Endpoint = "UpdateSeries"
+	UpdateEpisodeEndpoint   Endpoint = "UpdateEpisode"
+	UpdateClipEndpoint      Endpoint = "UpdateClip"
 )
 
 // CategoryID type
@@ -86,58 +101,21 @@ const (
 	Eurosport2Sweden       LiveChannelID = 2047
 )
 
-// LookupLiveChannelID using the channel name as specified in the MMS TitleService v1.2 documentation
+// LookupLiveChannelID using the channel name as specified in the MMS TitleService v1.2 documentation,
+// or an alias of the channel, in the DefaultChannelRegistry (see ChannelRegistry.Lookup)
 func LookupLiveChannelID(name string) (LiveChannelID, bool) {
-	id, ok := channelLookupTable[name]
-
-	return id, ok
+	return DefaultChannelRegistry.Lookup(name)
 }
 
-func validLiveChannelID(id LiveChannelID) bool {
-	switch id {
-	case
-		SVT1, SVT2, Discovery, Kanal5, TV3, TV4, Kanal9, DiscoveryWorld, DiscoveryScience,
-		SVTB, TV3SportHD, EsportsTV, Sportkanalen, Sjuan, TV4Film, TV6, TV4Sport, TV4Fakta, TV4Guld,
-		TV4Komedi, TV8, AnimalPlanet, SVT24, DiscoveryHDShowcase, TV12, Kunskapskanalen,
-		TV10, TLC, InvestigationDiscovery, TV4FaktaXL, Eurosport1, Kanal11, Eurosport2Sweden:
-		return true
+// String returns the name of the channel in the DefaultChannelRegistry, or the id if it is not registered
+func (id LiveChannelID) String() string {
+	if ch, ok := DefaultChannelRegistry.Channel(id); ok {
+		return ch.Name
 	}
 
-	return false
+	return strconv.Itoa(int(id))
 }
 
-var channelLookupTable = map[string]LiveChannelID{
-	"svt1":                  SVT1,
-	"svt2":                  SVT2,
-	"Discovery":             Discovery,
-	"Kanal5":                Kanal5,
-	"TV3":                   TV3,
-	"TV4":                   TV4,
-	"Kanal9":                Kanal9,
-	"Discovery World":       DiscoveryWorld,
-	"Discovery Science":     DiscoveryScience,
-	"svtB":                  SVTB,
-	"TV3 Sport HD":          TV3SportHD,
-	"Esports TV":            EsportsTV,
-	"Sportkanalen":          Sportkanalen,
-	"Sjuan":                 Sjuan,
-	"TV4 Film":              TV4Film,
-	"TV6":                   TV6,
-	"TV4 Sport":             TV4Sport,
-	"TV4 Fakta":             TV4Fakta,
-	"TV4 Guld":              TV4Guld,
-	"TV4 Komedi":            TV4Komedi,
-	"TV8":                   TV8,
-	"Animal Planet":         AnimalPlanet,
-	"svt24":                 SVT24,
-	"Discovery HD Showcase": DiscoveryHDShowcase,
-	"TV12":                  TV12,
-	"Kunskapskanalen":       Kunskapskanalen,
-	"TV10":                  TV10,
-	"TLC":                   TLC,
-	"Investigation Discovery": InvestigationDiscovery,
-	"TV4 Fakta XL":            TV4FaktaXL,
-	"Eurosport 1":             Eurosport1,
-	"Kanal 11":                Kanal11,
-	"Eurosport 2 Sweden":      Eurosport2Sweden,
+func validLiveChannelID(id LiveChannelID) bool {
+	return DefaultChannelRegistry.Valid(id)
 }
@@ -0,0 +1,228 @@
+package titleservice
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// EnsureOutcome is the outcome of ensuring that a title is registered
+type EnsureOutcome string
+
+// EnsureOutcomes
+const (
+	Created   EnsureOutcome = "created"   // registered by this call
+	Updated   EnsureOutcome = "updated"   // already registered with different or unknown content, and updated (see Upsert)
+	Unchanged EnsureOutcome = "unchanged" // already registered with the same content, nothing was sent
+	Conflict  EnsureOutcome = "conflict"  // already registered with different or unknown content
+)
+
+// HashStore remembers the content hash of every registered title,
+// keyed by "series:" + SeriesCode or "title:" + TitleCode
+type HashStore interface {
+	Get(key string) (hash string, ok bool, err error)
+	Put(key, hash string) error
+}
+
+// Hashes changes the HashStore used by the Ensure methods of the *client.
+// The default is an in-memory store, only remembering titles registered by the client.
+func Hashes(store HashStore) func(*Client) {
+	return func(c *Client) {
+		c.hashes = store
+	}
+}
+
+// EnsureSeries registers a Series unless a Series with the same SeriesCode has been registered before
+func (c *Client) EnsureSeries(ctx context.Context, series Series) (EnsureOutcome, error) {
+	return c.ensure(ctx, "series:"+series.SeriesCode, &series, UpdateSeriesEndpoint)
+}
+
+// EnsureEpisode registers an Episode unless a title with the same TitleCode has been registered before
+func (c *Client) EnsureEpisode(ctx context.Context, episode Episode) (EnsureOutcome, error) {
+	return c.ensure(ctx, "title:"+episode.TitleCode, &episode, UpdateEpisodeEndpoint)
+}
+
+// EnsureClip registers a Clip unless a title with the same TitleCode has been registered before
+func (c *Client) EnsureClip(ctx context.Context, clip Clip) (EnsureOutcome, error) {
+	return c.ensure(ctx, "title:"+clip.TitleCode, &clip, UpdateClipEndpoint)
+}
+
+// ensure compares the content hash of the request with the one in the HashStore.
+//
+// Unchanged is returned without sending the request if the hashes match. Conflict is returned,
+// with an error wrapping ErrAlreadyRegistered, if they differ or if the title is unknown to the
+// store but rejected by the MMS TitleService API as already registered. Otherwise the request is
+// sent, and its hash stored once registered (unless simulated).
+//
+// If the client is configured to Upsert, the request is sent to the updateEndpoint
+// instead of returning Conflict, and Updated is returned once updated.
+func (c *Client) ensure(ctx context.Context, key string, req Request, updateEndpoint Endpoint) (EnsureOutcome, error) {
+	params, err := req.Params()
+	if err != nil {
+		return "", newErrorWithMessage(err, string(req.Endpoint()))
+	}
+
+	hash := contentHash(req.Endpoint(), params.Encode())
+
+	stored, ok, err := c.hashes.Get(key)
+	if err != nil {
+		return "", err
+	}
+
+	if ok && stored == hash {
+		return Unchanged, nil
+	}
+
+	outcome := Created
+
+	if ok {
+		if !c.upsert {
+			return Conflict, newErrorWithMessage(ErrAlreadyRegistered, fmt.Sprintf("%s: %s is registered with different content", req.Endpoint(), key))
+		}
+
+		outcome, req = Updated, update{req, updateEndpoint}
+	}
+
+	_, err = c.send(ctx, c.newCall(req, params))
+
+	if errors.Is(err, ErrAlreadyRegistered) && outcome == Created {
+		if !c.upsert {
+			return Conflict, err
+		}
+
+		outcome, req = Updated, update{req, updateEndpoint}
+
+		_, err = c.send(ctx, c.newCall(req, params))
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if !c.simulate {
+		if err := c.hashes.Put(key, hash); err != nil {
+			return outcome, err
+		}
+	}
+
+	return outcome, nil
+}
+
+func contentHash(endpoint Endpoint, body string) string {
+	sum := sha256.Sum256([]byte(string(endpoint) + "?" + body))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryHashStore is a HashStore keeping the hashes in memory
+type MemoryHashStore struct {
+	mu     sync.Mutex
+	hashes map[string]string
+}
+
+// NewMemoryHashStore creates an empty MemoryHashStore
+func NewMemoryHashStore() *MemoryHashStore {
+	return &MemoryHashStore{hashes: map[string]string{}}
+}
+
+// Get the hash stored for the key
+func (s *MemoryHashStore) Get(key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash, ok := s.hashes[key]
+
+	return hash, ok, nil
+}
+
+// Put the hash for the key
+func (s *MemoryHashStore) Put(key, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hashes[key] = hash
+
+	return nil
+}
+
+// FileHashStore is a HashStore keeping the hashes in memory, and appending
+// every hash put to a file with one "key hash" pair per line, where the key is escaped
+// like a URL path segment so that a TitleCode or SeriesCode may contain whitespace
+type FileHashStore struct {
+	MemoryHashStore
+
+	file *os.File
+}
+
+// OpenFileHashStore loads the hashes in the file at path, creating it if it does not exist.
+// The caller should call Close when finished.
+func OpenFileHashStore(path string) (*FileHashStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileHashStore{
+		MemoryHashStore: MemoryHashStore{hashes: map[string]string{}},
+		file:            f,
+	}
+
+	sc := bufio.NewScanner(f)
+
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+
+		switch len(fields) {
+		case 0:
+			continue
+		case 2:
+			key, err := url.PathUnescape(fields[0])
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+
+			s.hashes[key] = fields[1]
+		default:
+			f.Close()
+			return nil, fmt.Errorf("%s:%d: expected key and hash", path, line)
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Put the hash for the key, and append it to the file
+func (s *FileHashStore) Put(key, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := fmt.Fprintf(s.file, "%s %s\n", url.PathEscape(key), hash); err != nil {
+		return err
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+
+	s.hashes[key] = hash
+
+	return nil
+}
+
+// Close the file
+func (s *FileHashStore) Close() error {
+	return s.file.Close()
+}
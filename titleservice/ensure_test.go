@@ -0,0 +1,231 @@
+package titleservice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testRegistryServer responds with 409 for SeriesCodes and TitleCodes already registered,
+// and with 404 for updates of those that are not
+func testRegistryServer(options ...func(*Client)) (*Client, func() int, func()) {
+	var (
+		mu         sync.Mutex
+		registered = map[string]bool{}
+		calls      int
+	)
+
+	ts, c := testServerAndClient(testUser, testPass, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls++
+
+		key := r.FormValue("SeriesCode")
+
+		if !strings.HasSuffix(r.URL.Path, "Series") {
+			key = r.FormValue("TitleCode")
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/Update") {
+			if !registered[key] {
+				testHandlerFunc(http.StatusNotFound, nil)(w, r)
+				return
+			}
+
+			testHandlerFunc(http.StatusOK, nil)(w, r)
+			return
+		}
+
+		if registered[key] {
+			testHandlerFunc(http.StatusConflict, nil)(w, r)
+			return
+		}
+
+		if _, simulate := r.Form["simulate"]; !simulate {
+			registered[key] = true
+		}
+
+		testHandlerFunc(http.StatusOK, nil)(w, r)
+	})
+
+	for _, f := range options {
+		f(c)
+	}
+
+	return c, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return calls
+	}, ts.Close
+}
+
+func TestClientEnsure(t *testing.T) {
+	ctx := context.Background()
+
+	series := MakeSeries("SC", "Series")
+	episode := MakeEpisode("TC1", "SC", "Episode", 60, Date(2017, 3, 27), Webisode)
+	clip := MakeClip("TC2", "Clip", 30, Date(2017, 3, 27))
+
+	t.Run("outcomes", func(t *testing.T) {
+		c, calls, close := testRegistryServer()
+		defer close()
+
+		changed := series
+		changed.Title = "Changed"
+
+		for _, tt := range []struct {
+			name  string
+			fn    func() (EnsureOutcome, error)
+			want  EnsureOutcome
+			err   error
+			calls int
+		}{
+			{"series created", func() (EnsureOutcome, error) { return c.EnsureSeries(ctx, series) }, Created, nil, 1},
+			{"series unchanged", func() (EnsureOutcome, error) { return c.EnsureSeries(ctx, series) }, Unchanged, nil, 1},
+			{"series changed", func() (EnsureOutcome, error) { return c.EnsureSeries(ctx, changed) }, Conflict, ErrAlreadyRegistered, 1},
+			{"episode created", func() (EnsureOutcome, error) { return c.EnsureEpisode(ctx, episode) }, Created, nil, 2},
+			{"episode unchanged", func() (EnsureOutcome, error) { return c.EnsureEpisode(ctx, episode) }, Unchanged, nil, 2},
+			{"clip created", func() (EnsureOutcome, error) { return c.EnsureClip(ctx, clip) }, Created, nil, 3},
+			{"clip unchanged", func() (EnsureOutcome, error) { return c.EnsureClip(ctx, clip) }, Unchanged, nil, 3},
+			{"invalid", func() (EnsureOutcome, error) { return c.EnsureClip(ctx, Clip{}) }, "", ErrMissingParameter, 3},
+		} {
+			got, err := tt.fn()
+
+			if got != tt.want {
+				t.Fatalf("%s: outcome = %q, want %q", tt.name, got, tt.want)
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("%s: err = %v, want %v", tt.name, err, tt.err)
+			}
+
+			if got, want := calls(), tt.calls; got != want {
+				t.Fatalf("%s: calls = %d, want %d", tt.name, got, want)
+			}
+		}
+	})
+
+	t.Run("registered elsewhere", func(t *testing.T) {
+		c, calls, close := testRegistryServer()
+		defer close()
+
+		if _, err := c.RegisterSeries(ctx, series); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, err := c.EnsureSeries(ctx, series)
+
+		if want := Conflict; got != want {
+			t.Fatalf("outcome = %q, want %q", got, want)
+		}
+
+		if ErrorCause(err) != ErrAlreadyRegistered {
+			t.Fatalf("err = %v, want %v", err, ErrAlreadyRegistered)
+		}
+
+		if got, want := calls(), 2; got != want {
+			t.Fatalf("calls = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("simulated", func(t *testing.T) {
+		c, calls, close := testRegistryServer(Simulate(true))
+		defer close()
+
+		for i := 0; i < 2; i++ {
+			if got, err := c.EnsureSeries(ctx, series); got != Created || err != nil {
+				t.Fatalf("outcome = %q, %v, want %q", got, err, Created)
+			}
+		}
+
+		if got, want := calls(), 2; got != want {
+			t.Fatalf("calls = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("file store", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "hashes")
+
+		store, err := OpenFileHashStore(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		c, calls, close := testRegistryServer(Hashes(store))
+		defer close()
+
+		if got, err := c.EnsureEpisode(ctx, episode); got != Created || err != nil {
+			t.Fatalf("outcome = %q, %v, want %q", got, err, Created)
+		}
+
+		store.Close()
+
+		store, err = OpenFileHashStore(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer store.Close()
+
+		Hashes(store)(c)
+
+		if got, err := c.EnsureEpisode(ctx, episode); got != Unchanged || err != nil {
+			t.Fatalf("outcome = %q, %v, want %q", got, err, Unchanged)
+		}
+
+		if got, want := calls(), 1; got != want {
+			t.Fatalf("calls = %d, want %d", got, want)
+		}
+	})
+}
+
+func TestOpenFileHashStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hashes")
+
+	if err := os.WriteFile(path, []byte("series:SC abc\n\ntitle:TC def\nseries:SC ghi\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store, err := OpenFileHashStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	for key, want := range map[string]string{"series:SC": "ghi", "title:TC": "def"} {
+		if got, ok, _ := store.Get(key); !ok || got != want {
+			t.Fatalf("store.Get(%q) = %q, %v, want %q", key, got, ok, want)
+		}
+	}
+
+	if err := store.Put("title:T C%", "jkl"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store.Close()
+
+	if store, err = OpenFileHashStore(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	if got, ok, _ := store.Get("title:T C%"); !ok || got != "jkl" {
+		t.Fatalf("store.Get(%q) = %q, %v, want %q", "title:T C%", got, ok, "jkl")
+	}
+
+	if err := os.WriteFile(path, []byte("series:SC\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := OpenFileHashStore(path); err == nil {
+		t.Fatalf("expected error for a line without a hash")
+	}
+}
@@ -7,7 +7,7 @@ import (
 )
 
 // MakeEpisode creates an Episode based on required parameters and optional parameters using options
-func MakeEpisode(titleCode, seriesCode, title string, length int, publishedAt string, categoryID CategoryID, options ...func(*Episode)) Episode {
+func MakeEpisode(titleCode, seriesCode, title string, length int, publishedAt MMSDate, categoryID CategoryID, options ...func(*Episode)) Episode {
 	e := &Episode{
 		TitleCode:   titleCode,
 		SeriesCode:  seriesCode,
@@ -24,25 +24,25 @@ func MakeEpisode(titleCode, seriesCode, title string, length int, publishedAt st
 	return *e
 }
 
-// Episode is a title linked to a series/season
+// Episode is a title linked to a series/season, with the category specific fields listed in CategoryRules
 type Episode struct {
 	TitleCode       string        `json:"title_code"`                  // required
 	SeriesCode      string        `json:"series_code"`                 // required
 	Title           string        `json:"title"`                       // required
 	Length          int           `json:"length"`                      // required
-	PublishedAt     string        `json:"published_at"`                // required (YYYYMMDD)
-	AvailableUntil  string        `json:"available_until,omitempty"`   // optional (YYYYMMDD)
+	PublishedAt     MMSDate       `json:"published_at"`                // required
+	AvailableUntil  MMSDate       `json:"available_until"`             // optional, not before PublishedAt
 	CategoryID      CategoryID    `json:"category_id"`                 // required
-	EpisodeNumber   int           `json:"episode_number,omitempty"`    // only applicable to Categories 1, 2, 4, 5
+	EpisodeNumber   int           `json:"episode_number,omitempty"`    // only applicable to categories 1, 2, 4, 5
 	Description     string        `json:"description,omitempty"`       // optional
 	LinkedTitleCode string        `json:"linked_title_code,omitempty"` // can only be reported for categories 2, 3, 8 (only for updates)
 	LiveTitle       string        `json:"live_title,omitempty"`        // obligatory for categories 1, 2, 3, 8
-	LiveTvDay       string        `json:"live_tv_day,omitempty"`       // obligatory for categories 1, 2, 3, 8 (YYYYMMDD)
-	LiveTime        string        `json:"live_time,omitempty"`         // obligatory for categories 1, 2, 3, 8 (HHMM, MMS-time: 23:45=2345, 01:45=2545, 02:00=0200)
+	LiveTvDay       MMSDate       `json:"live_tv_day"`                 // obligatory for categories 1, 2, 3, 8
+	LiveTime        MMSTime       `json:"live_time"`                   // obligatory for categories 1, 2, 3, 8 (MMS-time: 23:45=2345, 01:45=2545, 02:00=0200)
 	LiveChannelID   LiveChannelID `json:"live_channel_id,omitempty"`   // obligatory for categories 1, 2, 3, 8
 	PlayURL         string        `json:"play_url,omitempty"`          // maximum of 150 characters
-	TargetGroupCode string        `json:"target_group_code,omitempty"` // optional V = Vuxen (Adults) B = Barn (Children)
-	TerritoryCode   string        `json:"territory_code,omitempty"`    // optional S = Svenskt (Swedish) U = Utländskt (Foreign)
+	TargetGroupCode TargetGroup   `json:"target_group_code,omitempty"` // optional V = Vuxen (Adults) B = Barn (Children), checked by Validate
+	TerritoryCode   Territory     `json:"territory_code,omitempty"`    // optional S = Svenskt (Swedish) U = Utländskt (Foreign), checked by Validate
 	SuggestedGenre1 string        `json:"suggested_genre_1,omitempty"` // free text of maximum of 256 characters in length
 	SuggestedGenre2 string        `json:"suggested_genre_2,omitempty"` // free text of maximum of 256 characters in length
 	SuggestedGenre3 string        `json:"suggested_genre_3,omitempty"` // free text of maximum of 256 characters in length
@@ -64,57 +64,33 @@ func (e *Episode) Params() (url.Values, error) {
 		"SeriesCode":  {e.SeriesCode},
 		"Title":       {e.Title},
 		"Length":      {fmt.Sprintf("%d", e.Length)},
-		"PublishedAt": {e.PublishedAt},
+		"PublishedAt": {e.PublishedAt.String()},
 		"CategoryID":  {fmt.Sprintf("%d", e.CategoryID)},
 	}
 
-	if len(e.AvailableUntil) == 8 {
-		params.Set("AvailableUntil", e.AvailableUntil)
-	}
-
-	// only applicable to Categories 1, 2, 4, 5
-	switch e.CategoryID {
-	case TvProgram, TvSegment, Webisode, WebSegment:
-		if e.EpisodeNumber > 0 {
-			params.Set("EpisodeNumber", fmt.Sprintf("%d", e.EpisodeNumber))
-		}
+	if !e.AvailableUntil.IsZero() {
+		params.Set("AvailableUntil", e.AvailableUntil.String())
 	}
 
 	if e.Description != "" {
 		params.Set("Description", e.Description)
 	}
 
-	// can only be reported for Categories 2, 3, 8
-	switch e.CategoryID {
-	case TvSegment, TvExtra, Simulcast:
-		if e.LinkedTitleCode != "" {
-			params.Set("LinkedTitleCode", e.LinkedTitleCode)
-		}
-	}
-
-	// obligatory params for categories 1, 2, 3, 8
-	switch e.CategoryID {
-	case TvProgram, TvSegment, TvExtra, Simulcast:
-		params.Set("LiveTitle", e.LiveTitle)
-		params.Set("LiveTvDay", e.LiveTvDay)
-		params.Set("LiveTime", e.LiveTime)
-		params.Set("LiveChannelID", fmt.Sprintf("%d", e.LiveChannelID))
-	}
+	// EpisodeNumber, LinkedTitleCode and the live fields depending on the category
+	e.setCategoryParams(params)
 
 	// optional parameters
 
-	if strings.HasPrefix(e.PlayURL, "http") {
+	if e.PlayURL != "" {
 		params.Set("PlayUrl", e.PlayURL)
 	}
 
-	switch e.TargetGroupCode {
-	case Adults, Children:
-		params.Set("TargetGroupCode", e.TargetGroupCode)
+	if e.TargetGroupCode != "" {
+		params.Set("TargetGroupCode", string(e.TargetGroupCode))
 	}
 
-	switch e.TerritoryCode {
-	case Swedish, Foreign:
-		params.Set("TargetGroupCode", e.TargetGroupCode)
+	if e.TerritoryCode != "" {
+		params.Set("TerritoryCode", string(e.TerritoryCode))
 	}
 
 	if e.SuggestedGenre1 != "" {
@@ -132,54 +108,79 @@ func (e *Episode) Params() (url.Values, error) {
 	return params, nil
 }
 
-// Validate all fields
+// Validate all fields, returning a *ValidationError listing every invalid field
 func (e *Episode) Validate() error {
+	v := &ValidationError{Type: "Episode"}
+
 	if e.TitleCode == "" {
-		return newErrorWithMessage(ErrMissingParameter, "Episode TitleCode")
+		v.add("TitleCode", FieldMissing)
 	}
 
 	if e.SeriesCode == "" {
-		return newErrorWithMessage(ErrMissingParameter, "Episode SeriesCode")
+		v.add("SeriesCode", FieldMissing)
 	}
 
 	if e.Title == "" {
-		return newErrorWithMessage(ErrMissingParameter, "Episode Title")
+		v.add("Title", FieldMissing)
 	}
 
 	if e.Length < 1 {
-		return newErrorWithMessage(ErrInvalidParameter, "Episode Length")
+		v.add("Length", FieldBadFormat)
+	}
+
+	if e.PublishedAt.IsZero() {
+		v.add("PublishedAt", FieldMissing)
 	}
 
-	if len(e.PublishedAt) != 8 {
-		return newErrorWithMessage(ErrInvalidParameter, "Episode PublishedAt")
+	if !e.AvailableUntil.IsZero() && e.AvailableUntil.Before(e.PublishedAt) {
+		v.addf("AvailableUntil", FieldBadFormat, "before PublishedAt")
 	}
 
 	if !validCategoryID(e.CategoryID) {
-		return newErrorWithMessage(ErrInvalidParameter, "Episode CategoryID")
+		v.add("CategoryID", FieldBadFormat)
+	}
+
+	if e.EpisodeNumber < 0 {
+		v.add("EpisodeNumber", FieldBadFormat)
 	}
 
 	if strings.ContainsAny(e.Description, "<>") {
-		return newErrorWithMessage(ErrInvalidParameter, "Episode Description")
+		v.add("Description", FieldBadFormat)
 	}
 
-	switch e.CategoryID {
-	case TvProgram, TvSegment, TvExtra, Simulcast:
-		if e.LiveTitle == "" {
-			return newErrorWithMessage(ErrMissingParameter, "Episode LiveTitle")
-		}
+	e.validateCategory(v)
 
-		if len(e.LiveTvDay) != 8 {
-			return newErrorWithMessage(ErrInvalidParameter, "Episode LiveTvDay")
+	if r, ok := RuleFor(e.CategoryID); ok && r.Applies("LiveChannelID") && e.LiveChannelID != 0 {
+		if ch, ok := DefaultChannelRegistry.Channel(e.LiveChannelID); !ok {
+			v.add("LiveChannelID", FieldBadFormat)
+		} else if !ch.ValidOn(e.LiveTvDay) {
+			v.addf("LiveChannelID", FieldNotOnAir, fmt.Sprintf("%s not broadcasting on %s, only %s", ch.Name, e.LiveTvDay, ch.period()))
 		}
+	}
 
-		if len(e.LiveTime) != 4 {
-			return newErrorWithMessage(ErrInvalidParameter, "Episode LiveTime")
+	if e.PlayURL != "" {
+		if !validPlayURL(e.PlayURL) {
+			v.addf("PlayURL", FieldBadFormat, "absolute http or https URL")
 		}
 
-		if !validLiveChannelID(e.LiveChannelID) {
-			return newErrorWithMessage(ErrInvalidParameter, "Episode LiveChannelID")
-		}
+		v.maxLength("PlayURL", e.PlayURL, maxPlayURLLength)
+	}
+
+	switch e.TargetGroupCode {
+	case "", Adults, Children:
+	default:
+		v.addf("TargetGroupCode", FieldBadFormat, "V or B")
+	}
+
+	switch e.TerritoryCode {
+	case "", Swedish, Foreign:
+	default:
+		v.addf("TerritoryCode", FieldBadFormat, "S or U")
 	}
 
-	return nil
+	v.maxLength("SuggestedGenre1", e.SuggestedGenre1, maxSuggestedGenreLength)
+	v.maxLength("SuggestedGenre2", e.SuggestedGenre2, maxSuggestedGenreLength)
+	v.maxLength("SuggestedGenre3", e.SuggestedGenre3, maxSuggestedGenreLength)
+
+	return v.err()
 }
@@ -2,8 +2,10 @@ package titleservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -28,7 +30,7 @@ func TestRegisterEpisode(t *testing.T) {
 		func(e *Episode) {
 			e.LiveTitle = "episode-live-title"
 			e.LiveTvDay = Date(2017, 1, 2)
-			e.LiveTime = Time(10, 20)
+			e.LiveTime = BroadcastTime(10, 20)
 			e.LiveChannelID = TV4
 		},
 	))
@@ -46,19 +48,101 @@ func TestEpisodeValidate(t *testing.T) {
 		e    *Episode
 		want string
 	}{
-		{&Episode{}, "Episode TitleCode: missing parameter"},
-		{&Episode{TitleCode: "TC"}, "Episode SeriesCode: missing parameter"},
-		{&Episode{TitleCode: "TC", SeriesCode: "SC"}, "Episode Title: missing parameter"},
-		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T"}, "Episode Length: invalid parameter"},
-		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1}, "Episode PublishedAt: invalid parameter"},
-		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: "20070102"}, "Episode CategoryID: invalid parameter"},
-		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: "20070102", CategoryID: Webisode}, "<nil>"},
-		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: "20070102", CategoryID: Webisode, Description: "<b>"}, "Episode Description: invalid parameter"},
-		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: "20070102", CategoryID: TvProgram}, "Episode LiveTitle: missing parameter"},
-		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: "20070102", CategoryID: TvProgram, LiveTitle: "LT"}, "Episode LiveTvDay: invalid parameter"},
+		{&Episode{}, "Episode TitleCode: missing parameter; Episode SeriesCode: missing parameter; Episode Title: missing parameter; Episode Length: invalid parameter; Episode PublishedAt: missing parameter; Episode CategoryID: invalid parameter"},
+		{&Episode{TitleCode: "TC"}, "Episode SeriesCode: missing parameter; Episode Title: missing parameter; Episode Length: invalid parameter; Episode PublishedAt: missing parameter; Episode CategoryID: invalid parameter"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2)}, "Episode CategoryID: invalid parameter"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", PublishedAt: Date(2007, 1, 2), CategoryID: Webisode}, "Episode Length: invalid parameter"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, CategoryID: Webisode}, "Episode PublishedAt: missing parameter"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: Webisode}, "<nil>"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: Webisode, Description: "<b>"}, "Episode Description: invalid parameter"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: Webisode, EpisodeNumber: 3}, "<nil>"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: Webisode, EpisodeNumber: -1}, "Episode EpisodeNumber: invalid parameter"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: WebExtra, EpisodeNumber: 3}, "Episode EpisodeNumber: invalid parameter (only applicable to categories 1, 2, 4, 5)"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: Webisode, LinkedTitleCode: "LTC"}, "Episode LinkedTitleCode: invalid parameter (only applicable to categories 2, 3, 8)"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: TvProgram}, "Episode LiveTitle: missing parameter; Episode LiveTvDay: missing parameter; Episode LiveTime: missing parameter; Episode LiveChannelID: invalid parameter"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: TvProgram, LiveTitle: "LT", LiveTvDay: Date(2007, 1, 2), LiveTime: BroadcastTime(20, 15)}, "Episode LiveChannelID: invalid parameter"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: TvProgram, LiveTitle: "LT", LiveTvDay: Date(2007, 1, 2), LiveTime: BroadcastTime(20, 15), LiveChannelID: TV4}, "<nil>"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), AvailableUntil: Date(2007, 1, 1), CategoryID: Webisode}, "Episode AvailableUntil: invalid parameter (before PublishedAt)"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), AvailableUntil: Date(2007, 1, 2), CategoryID: Webisode}, "<nil>"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: Webisode, PlayURL: "www.example.com"}, "Episode PlayURL: invalid parameter (absolute http or https URL)"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: Webisode, PlayURL: "https://example.com/" + strings.Repeat("a", 131)}, "Episode PlayURL: invalid parameter (longer than 150 characters)"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: Webisode, TargetGroupCode: "X", TerritoryCode: "Y"}, "Episode TargetGroupCode: invalid parameter (V or B); Episode TerritoryCode: invalid parameter (S or U)"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: Webisode, SuggestedGenre2: strings.Repeat("å", 257)}, "Episode SuggestedGenre2: invalid parameter (longer than 256 characters)"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: Webisode, SuggestedGenre3: strings.Repeat("å", 256)}, "<nil>"},
+		{&Episode{TitleCode: "TC", SeriesCode: "SC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: TvProgram, LiveTitle: "LT", LiveTvDay: Date(2007, 1, 2), LiveTime: BroadcastTime(9, 45), LiveChannelID: TV4}, "<nil>"},
 	} {
 		if got := fmt.Sprintf("%v", tt.e.Validate()); got != tt.want {
 			t.Fatalf("tt.e.Validate() = %q, want %q", got, tt.want)
 		}
 	}
 }
+
+func TestEpisodeParams(t *testing.T) {
+	e := MakeEpisode("TC", "SC", "T", 1, Date(2007, 1, 2), Webisode, func(e *Episode) {
+		e.AvailableUntil = Date(2007, 2, 3)
+		e.PlayURL = "https://example.com/play"
+		e.TargetGroupCode = Children
+		e.TerritoryCode = Foreign
+	})
+
+	params, err := e.Params()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for key, want := range map[string]string{
+		"AvailableUntil":  "20070203",
+		"PlayUrl":         "https://example.com/play",
+		"TargetGroupCode": string(Children),
+		"TerritoryCode":   string(Foreign),
+	} {
+		if got := params.Get(key); got != want {
+			t.Fatalf("params.Get(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestEpisodeValidationError(t *testing.T) {
+	e := &Episode{TitleCode: "TC", Title: "T", Length: 1, PublishedAt: Date(2007, 1, 2), CategoryID: TvExtra, EpisodeNumber: 2, LiveTitle: "LT"}
+
+	_, err := e.Params()
+
+	var v *ValidationError
+
+	if !errors.As(err, &v) {
+		t.Fatalf("errors.As(err, &v) = false, want true")
+	}
+
+	for _, tt := range []struct {
+		field string
+		code  ValidationCode
+	}{
+		{"SeriesCode", FieldMissing},
+		{"EpisodeNumber", FieldNotAllowed},
+		{"LiveTvDay", FieldMissing},
+		{"LiveTime", FieldMissing},
+		{"LiveChannelID", FieldBadFormat},
+	} {
+		f := v.Field(tt.field)
+
+		if f == nil {
+			t.Fatalf("v.Field(%q) = nil, want FieldError", tt.field)
+		}
+
+		if got, want := f.Code, tt.code; got != want {
+			t.Fatalf("v.Field(%q).Code = %q, want %q", tt.field, got, want)
+		}
+	}
+
+	if got, want := len(v.Fields), 5; got != want {
+		t.Fatalf("len(v.Fields) = %d, want %d", got, want)
+	}
+
+	if !errors.Is(err, ErrMissingParameter) {
+		t.Fatalf("errors.Is(err, ErrMissingParameter) = false, want true")
+	}
+
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("errors.Is(err, ErrInvalidParameter) = false, want true")
+	}
+}
@@ -1,6 +1,11 @@
 package titleservice
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
 
 var (
 	// ErrUnexpectedContentType is returned if response isn't JSON
@@ -27,10 +32,74 @@ var (
 	// ErrAlreadyRegistered is returned on status 409 from the MMS TitleService API
 	ErrAlreadyRegistered = errors.New("already registered (conflict)")
 
+	// ErrNotRegistered is returned on status 404 from an Update endpoint of the MMS TitleService API,
+	// when updating a title that isn't registered
+	ErrNotRegistered = errors.New("not registered (not found)")
+
+	// ErrNotFound is returned on status 404 from any other endpoint, e.g. with a wrong BaseURL
+	ErrNotFound = errors.New("not found")
+
 	// ErrInternalServerError is returned on status 500 from the MMS TitleService API
 	ErrInternalServerError = errors.New("internal server error")
+
+	// ErrTooManyRequests is returned on status 429 from the MMS TitleService API
+	ErrTooManyRequests = errors.New("too many requests")
+
+	// ErrServiceUnavailable is returned on status 502, 503 and 504 from the MMS TitleService API
+	ErrServiceUnavailable = errors.New("service unavailable")
 )
 
+// APIError is returned when the MMS TitleService API responds with an error status.
+//
+// The sentinel error for the status (such as ErrAlreadyRegistered) can be matched
+// using errors.Is, or retrieved using ErrorCause.
+type APIError struct {
+	StatusCode        int           // HTTP status code
+	Status            string        // HTTP status line, e.g. "409 Conflict"
+	Endpoint          Endpoint      // endpoint the request was sent to
+	StatusDescription string        // from the response body, if any
+	Errors            []string      // from the response body, if any
+	RetryAfter        time.Duration // from the Retry-After header, if any
+
+	err error
+}
+
+func (e *APIError) Error() string {
+	msg := string(e.Endpoint) + ": " + e.err.Error()
+
+	if len(e.Errors) > 0 {
+		msg += ": " + strings.Join(e.Errors, "; ")
+	}
+
+	return msg
+}
+
+// Unwrap returns the sentinel error for the status
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// Cause returns the sentinel error for the status
+func (e *APIError) Cause() error {
+	return e.err
+}
+
+// Temporary returns true if the MMS TitleService API is overloaded or unavailable
+func (e *APIError) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// Retryable returns true if sending the same request again might succeed
+func (e *APIError) Retryable() bool {
+	return e.Temporary() || e.StatusCode == http.StatusInternalServerError
+}
+
 // newErrorWithMessage annotates err with a new message.
 // If err is nil, newErrorWithMessage returns nil.
 func newErrorWithMessage(err error, msg string) error {
@@ -57,15 +126,30 @@ func (e *errorWithMessage) Cause() error {
 	return e.cause
 }
 
+// Unwrap returns the annotated error, allowing it to be matched using errors.Is and errors.As
+func (e *errorWithMessage) Unwrap() error {
+	return e.cause
+}
+
 // ErrorCause returns the underlying cause of the error, if possible.
-// An error value has a cause if it implements the following
-// interface:
+// An error value has a cause if it implements one of the following
+// interfaces:
 //
 //     type causer interface {
 //            Cause() error
 //     }
 //
-// If the error does not implement Cause, the original error will
+//     type wrapper interface {
+//            Unwrap() error
+//     }
+//
+//     type joiner interface {
+//            Unwrap() []error
+//     }
+//
+// For joined errors, the cause of the first non-nil error is returned.
+//
+// If the error does not implement any of them, the original error will
 // be returned. If the error is nil, nil will be returned without further
 // investigation.
 func ErrorCause(err error) error {
@@ -73,12 +157,37 @@ func ErrorCause(err error) error {
 		Cause() error
 	}
 
+	type wrapper interface {
+		Unwrap() error
+	}
+
+	type joiner interface {
+		Unwrap() []error
+	}
+
 	for err != nil {
-		cause, ok := err.(causer)
-		if !ok {
+		var next error
+
+		switch e := err.(type) {
+		case causer:
+			next = e.Cause()
+		case wrapper:
+			next = e.Unwrap()
+		case joiner:
+			for _, joined := range e.Unwrap() {
+				if joined != nil {
+					next = joined
+					break
+				}
+			}
+		}
+
+		if next == nil {
 			break
 		}
-		err = cause.Cause()
+
+		err = next
 	}
+
 	return err
 }
@@ -0,0 +1,147 @@
+package titleservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	t.Run("json_body", func(t *testing.T) {
+		ts, c := testServerAndClient(testUser, testPass, testHandlerFunc(http.StatusConflict, []string{"TitleCode already registered"}))
+		defer ts.Close()
+
+		r, err := c.RegisterSeries(context.Background(), MakeSeries("series-code", "series-title"))
+
+		var apiErr *APIError
+
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("errors.As(err, &apiErr) = false, want true")
+		}
+
+		if !errors.Is(err, ErrAlreadyRegistered) {
+			t.Fatalf("errors.Is(err, ErrAlreadyRegistered) = false, want true")
+		}
+
+		if got, want := ErrorCause(err), ErrAlreadyRegistered; got != want {
+			t.Fatalf("ErrorCause(err) = %v, want %v", got, want)
+		}
+
+		if got, want := apiErr.StatusCode, http.StatusConflict; got != want {
+			t.Fatalf("apiErr.StatusCode = %d, want %d", got, want)
+		}
+
+		if got, want := apiErr.Endpoint, RegisterSeriesEndpoint; got != want {
+			t.Fatalf("apiErr.Endpoint = %q, want %q", got, want)
+		}
+
+		if got, want := apiErr.StatusDescription, "Conflict"; got != want {
+			t.Fatalf("apiErr.StatusDescription = %q, want %q", got, want)
+		}
+
+		if got, want := err.Error(), "RegisterSeries: already registered (conflict): TitleCode already registered"; got != want {
+			t.Fatalf("err.Error() = %q, want %q", got, want)
+		}
+
+		if got, want := len(r.Errors), 1; got != want {
+			t.Fatalf("len(r.Errors) = %d, want %d", got, want)
+		}
+
+		if got, want := r.Errors[0], "TitleCode already registered"; got != want {
+			t.Fatalf("r.Errors[0] = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("plain_body", func(t *testing.T) {
+		ts, c := testServerAndClient(testUser, testPass, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "3")
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		})
+		defer ts.Close()
+
+		r, err := c.RegisterClip(context.Background(), MakeClip("clip-title-code", "clip-title", 123, Date(2017, 3, 27)))
+
+		var apiErr *APIError
+
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("errors.As(err, &apiErr) = false, want true")
+		}
+
+		if got, want := apiErr.RetryAfter.Seconds(), 3.0; got != want {
+			t.Fatalf("apiErr.RetryAfter.Seconds() = %v, want %v", got, want)
+		}
+
+		if got, want := r.StatusDescription, "503 Service Unavailable"; got != want {
+			t.Fatalf("r.StatusDescription = %q, want %q", got, want)
+		}
+
+		if got, want := r.Errors[0], ErrServiceUnavailable.Error(); got != want {
+			t.Fatalf("r.Errors[0] = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestErrorCause(t *testing.T) {
+	wrapped := newErrorWithMessage(ErrInvalidParameter, "Episode Length")
+
+	for _, tt := range []struct {
+		err  error
+		want error
+	}{
+		{nil, nil},
+		{ErrNoUsername, ErrNoUsername},
+		{wrapped, ErrInvalidParameter},
+		{newErrorWithMessage(wrapped, "RegisterEpisode"), ErrInvalidParameter},
+		{fmt.Errorf("wrapped: %w", wrapped), ErrInvalidParameter},
+		{&APIError{err: ErrAlreadyRegistered}, ErrAlreadyRegistered},
+		{testJoinedErrors{nil, wrapped, ErrNoPassword}, ErrInvalidParameter},
+		{testJoinedErrors{}, testJoinedErrors{}},
+	} {
+		if got := ErrorCause(tt.err); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Fatalf("ErrorCause(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+
+	if !errors.Is(wrapped, ErrInvalidParameter) {
+		t.Fatalf("errors.Is(wrapped, ErrInvalidParameter) = false, want true")
+	}
+}
+
+type testJoinedErrors []error
+
+func (e testJoinedErrors) Error() string {
+	return fmt.Sprintf("%d joined errors", len(e))
+}
+
+func (e testJoinedErrors) Unwrap() []error {
+	return e
+}
+
+func TestAPIErrorRetryable(t *testing.T) {
+	for _, tt := range []struct {
+		statusCode int
+		temporary  bool
+		retryable  bool
+	}{
+		{http.StatusBadRequest, false, false},
+		{http.StatusForbidden, false, false},
+		{http.StatusConflict, false, false},
+		{http.StatusTooManyRequests, true, true},
+		{http.StatusInternalServerError, false, true},
+		{http.StatusBadGateway, true, true},
+		{http.StatusServiceUnavailable, true, true},
+		{http.StatusGatewayTimeout, true, true},
+	} {
+		e := &APIError{StatusCode: tt.statusCode}
+
+		if got, want := e.Temporary(), tt.temporary; got != want {
+			t.Fatalf("[%d] e.Temporary() = %v, want %v", tt.statusCode, got, want)
+		}
+
+		if got, want := e.Retryable(), tt.retryable; got != want {
+			t.Fatalf("[%d] e.Retryable() = %v, want %v", tt.statusCode, got, want)
+		}
+	}
+}
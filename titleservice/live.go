@@ -0,0 +1,75 @@
+package titleservice
+
+import (
+	"fmt"
+	"time"
+)
+
+// Broadcast is a Live TV broadcast of an Episode
+type Broadcast struct {
+	Title   string        // the title in the TV listing, LiveTitle of the Episode
+	Start   time.Time     // the start of the broadcast
+	Channel LiveChannelID // the channel broadcasting the episode
+}
+
+// Day returns the broadcast day in Stockholm, where the day changes at 02:00,
+// or the zero MMSDate if Start is unset
+func (b Broadcast) Day() MMSDate {
+	if b.Start.IsZero() {
+		return MMSDate{}
+	}
+
+	return BroadcastDateAtTime(b.Start)
+}
+
+// Time returns the MMS Live TV broadcast time in Stockholm, between 0200 and 2559,
+// or the zero MMSTime if Start is unset
+func (b Broadcast) Time() MMSTime {
+	if b.Start.IsZero() {
+		return MMSTime{}
+	}
+
+	return BroadcastTimeAtTime(b.Start)
+}
+
+// MakeLiveEpisode creates an Episode of a category broadcast on Live TV, with the live fields
+// derived from b. It returns an error if the category is not broadcast on Live TV, and
+// a *ValidationError if the episode is invalid.
+func MakeLiveEpisode(titleCode, seriesCode, title string, length int, publishedAt MMSDate, categoryID CategoryID, b Broadcast, options ...func(*Episode)) (Episode, error) {
+	if r, ok := RuleFor(categoryID); !ok || r.Field("LiveTvDay") != FieldRequired {
+		return Episode{}, newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("category %d is not broadcast on Live TV", categoryID))
+	}
+
+	e := MakeEpisode(titleCode, seriesCode, title, length, publishedAt, categoryID,
+		append([]func(*Episode){WithBroadcast(b)}, options...)...,
+	)
+
+	if err := e.Validate(); err != nil {
+		return Episode{}, err
+	}
+
+	return e, nil
+}
+
+// MakeTvProgram creates a TvProgram Episode broadcast on channel at broadcastStart, using the
+// title as LiveTitle. It returns a *ValidationError if the episode is invalid.
+func MakeTvProgram(titleCode, seriesCode, title string, length int, publishedAt MMSDate, broadcastStart time.Time, channel LiveChannelID, options ...func(*Episode)) (Episode, error) {
+	return MakeLiveEpisode(titleCode, seriesCode, title, length, publishedAt, TvProgram,
+		Broadcast{Title: title, Start: broadcastStart, Channel: channel}, options...,
+	)
+}
+
+// MakeSimulcast creates a Simulcast Episode streamed while broadcast on channel at broadcastStart,
+// using the title as LiveTitle and publishing it at the date of the broadcast in Stockholm.
+// It returns a *ValidationError if the episode is invalid.
+func MakeSimulcast(titleCode, seriesCode, title string, length int, broadcastStart time.Time, channel LiveChannelID, options ...func(*Episode)) (Episode, error) {
+	var publishedAt MMSDate
+
+	if !broadcastStart.IsZero() {
+		publishedAt = DateAtTime(broadcastStart)
+	}
+
+	return MakeLiveEpisode(titleCode, seriesCode, title, length, publishedAt, Simulcast,
+		Broadcast{Title: title, Start: broadcastStart, Channel: channel}, options...,
+	)
+}
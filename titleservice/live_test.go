@@ -0,0 +1,102 @@
+package titleservice
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBroadcast(t *testing.T) {
+	for _, tt := range []struct {
+		start time.Time
+		day   string
+		time  string
+	}{
+		{time.Date(2017, 3, 27, 20, 15, 0, 0, Stockholm), "20170327", "2015"},
+		{time.Date(2017, 3, 28, 1, 45, 0, 0, Stockholm), "20170327", "2545"},
+		{time.Date(2017, 3, 28, 2, 0, 0, 0, Stockholm), "20170328", "0200"},
+		{time.Date(2017, 3, 27, 22, 30, 0, 0, time.UTC), "20170327", "2430"},
+		{time.Time{}, "", ""},
+	} {
+		b := Broadcast{Start: tt.start}
+
+		if got := b.Day().String(); got != tt.day {
+			t.Fatalf("Broadcast{Start: %v}.Day() = %q, want %q", tt.start, got, tt.day)
+		}
+
+		if got := b.Time().String(); got != tt.time {
+			t.Fatalf("Broadcast{Start: %v}.Time() = %q, want %q", tt.start, got, tt.time)
+		}
+	}
+}
+
+func TestMakeTvProgram(t *testing.T) {
+	start := time.Date(2017, 3, 28, 0, 30, 0, 0, Stockholm)
+
+	e, err := MakeTvProgram("TC", "SC", "T", 60, Date(2017, 3, 28), start, TV4, WithLiveTitle("LT"), WithEpisodeNumber(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := fmt.Sprintln(e.CategoryID, e.LiveTitle, e.LiveTvDay, e.LiveTime, e.LiveChannelID, e.EpisodeNumber), "1 LT 20170327 2430 TV4 2\n"; got != want {
+		t.Fatalf("episode = %q, want %q", got, want)
+	}
+
+	for _, tt := range []struct {
+		start   time.Time
+		channel LiveChannelID
+		want    string
+	}{
+		{time.Time{}, TV4, "Episode LiveTvDay: missing parameter; Episode LiveTime: missing parameter"},
+		{start, 0, "Episode LiveChannelID: invalid parameter"},
+		{start, 1, "Episode LiveChannelID: invalid parameter"},
+	} {
+		_, err := MakeTvProgram("TC", "SC", "T", 60, Date(2017, 3, 28), tt.start, tt.channel)
+
+		var v *ValidationError
+
+		if !errors.As(err, &v) {
+			t.Fatalf("err = %v, want *ValidationError", err)
+		}
+
+		if got := err.Error(); got != tt.want {
+			t.Fatalf("err.Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestMakeSimulcast(t *testing.T) {
+	start := time.Date(2017, 3, 28, 1, 0, 0, 0, Stockholm)
+
+	e, err := MakeSimulcast("TC", "SC", "T", 60, start, TV4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := fmt.Sprintln(e.CategoryID, e.PublishedAt, e.LiveTitle, e.LiveTvDay, e.LiveTime), "8 20170328 T 20170327 2500\n"; got != want {
+		t.Fatalf("episode = %q, want %q", got, want)
+	}
+
+	if _, err := MakeSimulcast("TC", "SC", "T", 60, start, TV4, WithEpisodeNumber(1)); !errors.Is(err, ErrInvalidParameter) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidParameter)
+	}
+}
+
+func TestMakeLiveEpisode(t *testing.T) {
+	b := Broadcast{Title: "LT", Start: time.Date(2017, 3, 27, 20, 0, 0, 0, Stockholm), Channel: TV4}
+
+	for _, id := range []CategoryID{TvProgram, TvSegment, TvExtra, Simulcast} {
+		if _, err := MakeLiveEpisode("TC", "SC", "T", 60, Date(2017, 3, 27), id, b); err != nil {
+			t.Fatalf("unexpected error for category %d: %v", id, err)
+		}
+	}
+
+	for _, id := range []CategoryID{Webisode, WebClip, ChannelSimulcast, WebLiveBroadcast, 0} {
+		_, err := MakeLiveEpisode("TC", "SC", "T", 60, Date(2017, 3, 27), id, b)
+
+		if got, want := fmt.Sprint(err), fmt.Sprintf("category %d is not broadcast on Live TV: invalid parameter", id); got != want {
+			t.Fatalf("err = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package titleservice
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"time"
+)
+
+// Logger configures the *client to log every request sent to the MMS TitleService API using l.
+//
+// Successful requests are logged at Info, rejected requests (4xx) at Warn, and failed
+// requests at Error. The form body is logged at Debug. The password is never logged.
+func Logger(l *slog.Logger) func(*Client) {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// logRequest logs the form body of the request at Debug, with the password redacted
+func (c *Client) logRequest(ctx context.Context, endpoint Endpoint, params url.Values, attempt int) {
+	if c.logger == nil || !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	form := c.form(params)
+
+	form.Set("pass", "[REDACTED]")
+
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "titleservice request",
+		append(c.logAttrs(endpoint, params, attempt), slog.String("body", form.Encode()))...,
+	)
+}
+
+// logResponse logs the outcome of the request at a level depending on err
+func (c *Client) logResponse(ctx context.Context, endpoint Endpoint, params url.Values, attempt int, d time.Duration, resp *Response, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	level := slog.LevelInfo
+
+	attrs := append(c.logAttrs(endpoint, params, attempt), slog.Duration("duration", d))
+
+	var apiErr *APIError
+
+	switch {
+	case err == nil:
+		if resp != nil {
+			attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		}
+	case errors.As(err, &apiErr):
+		level = slog.LevelWarn
+
+		if apiErr.StatusCode >= 500 || apiErr.Temporary() {
+			level = slog.LevelError
+		}
+
+		attrs = append(attrs, slog.Int("status", apiErr.StatusCode))
+
+		if len(apiErr.Errors) > 0 {
+			attrs = append(attrs, slog.Any("errors", apiErr.Errors))
+		}
+
+		attrs = append(attrs, slog.String("error", err.Error()))
+	default:
+		level = slog.LevelError
+
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	c.logger.LogAttrs(ctx, level, "titleservice response", attrs...)
+}
+
+func (c *Client) logAttrs(endpoint Endpoint, params url.Values, attempt int) []slog.Attr {
+	attrs := []slog.Attr{slog.String("endpoint", string(endpoint))}
+
+	if code := params.Get("TitleCode"); code != "" {
+		attrs = append(attrs, slog.String("title_code", code))
+	}
+
+	if code := params.Get("SeriesCode"); code != "" {
+		attrs = append(attrs, slog.String("series_code", code))
+	}
+
+	return append(attrs, slog.Bool("simulate", c.simulate), slog.Int("attempt", attempt))
+}
@@ -0,0 +1,142 @@
+package titleservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	ctx := context.Background()
+
+	clip := MakeClip("TC", "Clip", 30, Date(2017, 3, 27))
+
+	for _, tt := range []struct {
+		name   string
+		hf     http.HandlerFunc
+		client func(c *Client)
+		level  string
+		status float64
+		errors []interface{}
+	}{
+		{"ok", testHandlerFunc(http.StatusOK, nil), nil, "INFO", 200, nil},
+		{"conflict", testHandlerFunc(http.StatusConflict, []string{"TitleCode TC is already registered"}), nil, "WARN", 409, []interface{}{"TitleCode TC is already registered"}},
+		{"service_unavailable", testHandlerFunc(http.StatusServiceUnavailable, nil), nil, "ERROR", 503, nil},
+		{"network_error", nil, func(c *Client) {
+			c.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			})}
+		}, "ERROR", 0, nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, c := testServerAndClient(testUser, testPass, tt.hf)
+			defer ts.Close()
+
+			var buf bytes.Buffer
+
+			Logger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))(c)
+			Simulate(true)(c)
+
+			if tt.client != nil {
+				tt.client(c)
+			}
+
+			c.RegisterClip(ctx, clip)
+
+			if strings.Contains(buf.String(), testPass) {
+				t.Fatalf("the password was logged: %s", buf.String())
+			}
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+			if got, want := len(lines), 2; got != want {
+				t.Fatalf("len(lines) = %d, want %d", got, want)
+			}
+
+			var request, response map[string]interface{}
+
+			json.Unmarshal([]byte(lines[0]), &request)
+			json.Unmarshal([]byte(lines[1]), &response)
+
+			if got, want := request["level"], "DEBUG"; got != want {
+				t.Fatalf("request[\"level\"] = %v, want %v", got, want)
+			}
+
+			if got, want := request["body"], "Length=30&PublishedAt=20170327&Title=Clip&TitleCode=TC&pass=%5BREDACTED%5D&simulate=&user="+testUser; got != want {
+				t.Fatalf("request[\"body\"] = %v, want %v", got, want)
+			}
+
+			for key, want := range map[string]interface{}{
+				"level":      tt.level,
+				"endpoint":   "RegisterClip",
+				"title_code": "TC",
+				"simulate":   true,
+				"attempt":    1.0,
+			} {
+				if got := response[key]; got != want {
+					t.Fatalf("response[%q] = %v, want %v", key, got, want)
+				}
+			}
+
+			if got, _ := response["status"].(float64); got != tt.status {
+				t.Fatalf("response[\"status\"] = %v, want %v", got, tt.status)
+			}
+
+			if _, ok := response["duration"]; !ok {
+				t.Fatalf("response has no duration")
+			}
+
+			if got, _ := response["errors"].([]interface{}); len(got) != len(tt.errors) || len(got) > 0 && got[0] != tt.errors[0] {
+				t.Fatalf("response[\"errors\"] = %v, want %v", got, tt.errors)
+			}
+
+			if _, ok := response["error"]; ok != (tt.level != "INFO") {
+				t.Fatalf("response[\"error\"] = %v, want error: %v", response["error"], tt.level != "INFO")
+			}
+		})
+	}
+
+	t.Run("info", func(t *testing.T) {
+		ts, c := testServerAndClient(testUser, testPass, testHandlerFunc(http.StatusOK, nil))
+		defer ts.Close()
+
+		var buf bytes.Buffer
+
+		Logger(slog.New(slog.NewTextHandler(&buf, nil)))(c)
+
+		c.RegisterSeries(ctx, MakeSeries("SC", "Series"))
+
+		if got := buf.String(); strings.Contains(got, "body=") || !strings.Contains(got, "series_code=SC") {
+			t.Fatalf("buf = %q, want a single response without the body", got)
+		}
+	})
+
+	t.Run("params not modified", func(t *testing.T) {
+		ts, c := testServerAndClient(testUser, testPass, testHandlerFunc(http.StatusOK, nil))
+		defer ts.Close()
+
+		var call Call
+
+		Use(func(next Handler) Handler {
+			return func(ctx context.Context, c Call) (*Response, error) {
+				call = c
+				return next(ctx, c)
+			}
+		})(c)
+
+		if _, err := c.RegisterClip(ctx, clip); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, key := range []string{"user", "pass", "simulate"} {
+			if _, ok := call.Params[key]; ok {
+				t.Fatalf("call.Params contains %q after the request was sent", key)
+			}
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package titleservice
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Outcome of a request to the MMS TitleService API, as reported to Metrics
+type Outcome string
+
+// Outcomes
+const (
+	OutcomeOK             Outcome = "ok"
+	OutcomeInvalid        Outcome = "invalid"         // 400
+	OutcomeAuth           Outcome = "auth"            // 403
+	OutcomeNotFound       Outcome = "not_found"       // 404
+	OutcomeConflict       Outcome = "conflict"        // 409
+	OutcomeRateLimited    Outcome = "rate_limited"    // 429
+	OutcomeServerError    Outcome = "server_error"    // 5xx, or a response that can't be decoded
+	OutcomeTransportError Outcome = "transport_error" // no response, e.g. timeouts and network errors
+)
+
+// Metrics receives measurements of every request sent by a Client, such as the
+// Prometheus adapter in github.com/TV4/mms/titleservice/prometheus.
+// The methods are called concurrently by requests in flight.
+type Metrics interface {
+	// RequestStarted is called before each attempt to send a request
+	RequestStarted(endpoint Endpoint)
+
+	// RequestFinished is called after each attempt, with its outcome and duration
+	RequestFinished(endpoint Endpoint, outcome Outcome, d time.Duration)
+
+	// RequestRetried is called before a failed request is sent again
+	RequestRetried(endpoint Endpoint)
+}
+
+// Instrument configures the *client to report measurements of its requests to m
+func Instrument(m Metrics) func(*Client) {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// outcome classifies the error returned by do
+func outcome(err error) Outcome {
+	if err == nil {
+		return OutcomeOK
+	}
+
+	var apiErr *APIError
+
+	if !errors.As(err, &apiErr) {
+		var urlErr *url.Error
+
+		if errors.As(err, &urlErr) {
+			return OutcomeTransportError
+		}
+
+		return OutcomeServerError
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest:
+		return OutcomeInvalid
+	case http.StatusForbidden:
+		return OutcomeAuth
+	case http.StatusNotFound:
+		return OutcomeNotFound
+	case http.StatusConflict:
+		return OutcomeConflict
+	case http.StatusTooManyRequests:
+		return OutcomeRateLimited
+	}
+
+	return OutcomeServerError
+}
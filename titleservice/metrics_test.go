@@ -0,0 +1,126 @@
+package titleservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testMetrics struct {
+	mu       sync.Mutex
+	inFlight int
+	events   []string
+}
+
+func (m *testMetrics) RequestStarted(endpoint Endpoint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight++
+	m.events = append(m.events, "started "+string(endpoint))
+}
+
+func (m *testMetrics) RequestFinished(endpoint Endpoint, outcome Outcome, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight--
+	m.events = append(m.events, fmt.Sprintf("finished %s %s", endpoint, outcome))
+}
+
+func (m *testMetrics) RequestRetried(endpoint Endpoint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, "retried "+string(endpoint))
+}
+
+func TestInstrument(t *testing.T) {
+	statuses := []int{http.StatusServiceUnavailable, http.StatusOK}
+
+	ts, c := testServerAndClient(testUser, testPass, func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[0]
+		statuses = statuses[1:]
+
+		testHandlerFunc(status, nil)(w, r)
+	})
+	defer ts.Close()
+
+	m := &testMetrics{}
+
+	Instrument(m)(c)
+	Retry(ExponentialBackoff{MaxAttempts: 2, BaseDelay: time.Millisecond})(c)
+
+	if _, err := c.RegisterSeries(context.Background(), MakeSeries("SC", "Series")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"started RegisterSeries",
+		"finished RegisterSeries server_error",
+		"retried RegisterSeries",
+		"started RegisterSeries",
+		"finished RegisterSeries ok",
+	}
+
+	if got := m.events; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("m.events = %q, want %q", got, want)
+	}
+
+	if got, want := m.inFlight, 0; got != want {
+		t.Fatalf("m.inFlight = %d, want %d", got, want)
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	series := MakeSeries("SC", "Series")
+
+	for _, tt := range []struct {
+		status int
+		want   Outcome
+	}{
+		{http.StatusOK, OutcomeOK},
+		{http.StatusBadRequest, OutcomeInvalid},
+		{http.StatusForbidden, OutcomeAuth},
+		{http.StatusNotFound, OutcomeNotFound},
+		{http.StatusConflict, OutcomeConflict},
+		{http.StatusTooManyRequests, OutcomeRateLimited},
+		{http.StatusInternalServerError, OutcomeServerError},
+		{http.StatusGatewayTimeout, OutcomeServerError},
+	} {
+		ts, c := testServerAndClient(testUser, testPass, testHandlerFunc(tt.status, nil))
+
+		_, err := c.RegisterSeries(context.Background(), series)
+
+		ts.Close()
+
+		if got := outcome(err); got != tt.want {
+			t.Fatalf("outcome(%v) = %q, want %q", err, got, tt.want)
+		}
+	}
+
+	c := testClient(HTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})}))
+
+	_, err := c.RegisterSeries(context.Background(), series)
+
+	if got, want := outcome(err), OutcomeTransportError; got != want {
+		t.Fatalf("outcome(%v) = %q, want %q", err, got, want)
+	}
+
+	ts, c := testServerAndClient(testUser, testPass, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+	})
+	defer ts.Close()
+
+	_, err = c.RegisterSeries(context.Background(), series)
+
+	if got, want := outcome(err), OutcomeServerError; got != want {
+		t.Fatalf("outcome(%v) = %q, want %q", err, got, want)
+	}
+}
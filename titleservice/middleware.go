@@ -0,0 +1,58 @@
+package titleservice
+
+import (
+	"context"
+	"net/url"
+)
+
+// Call is a request to the MMS TitleService API, passed through the middleware of a Client
+type Call struct {
+	Endpoint Endpoint   // the endpoint the request is sent to
+	Request  Request    // the *Series, *Episode or *Clip
+	Params   url.Values // the parameters encoded from Request, without the credentials
+	Simulate bool       // true if the request is simulated
+}
+
+// Handler sends a Call to the MMS TitleService API
+type Handler func(ctx context.Context, call Call) (*Response, error)
+
+// Middleware wraps a Handler, e.g. to log, measure or rewrite calls.
+//
+// The Params of a Call are sent as they are when it reaches the Client, so rewriting
+// a field means changing Params. A Middleware may also respond without calling next.
+type Middleware func(next Handler) Handler
+
+// Use adds middleware to the *client. The first middleware is the outermost,
+// seeing every call first and every response last.
+func Use(middleware ...Middleware) func(*Client) {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// newCall returns the Call for a request and its encoded params
+func (c *Client) newCall(req Request, params url.Values) Call {
+	call := Call{Endpoint: req.Endpoint(), Request: req, Params: params, Simulate: c.simulate}
+
+	if u, ok := req.(update); ok {
+		call.Request = u.Request
+	}
+
+	return call
+}
+
+// send the call through the middleware of the client, in a span if the client has a Tracer
+func (c *Client) send(ctx context.Context, call Call) (_ *Response, err error) {
+	ctx, end := c.startSpan(ctx, call)
+	defer func() { end(err) }()
+
+	h := func(ctx context.Context, call Call) (*Response, error) {
+		return c.post(ctx, call.Endpoint, call.Params)
+	}
+
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+
+	return h(ctx, call)
+}
@@ -0,0 +1,125 @@
+package titleservice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUse(t *testing.T) {
+	ctx := context.Background()
+
+	clip := MakeClip("TC", "Clip", 30, Date(2017, 3, 27), WithClipPlayURL("http://www.tv4play.se/play/TC"))
+
+	t.Run("order", func(t *testing.T) {
+		var log []string
+
+		trace := func(name string) Middleware {
+			return func(next Handler) Handler {
+				return func(ctx context.Context, call Call) (*Response, error) {
+					log = append(log, name+">"+string(call.Endpoint))
+
+					resp, err := next(ctx, call)
+
+					log = append(log, "<"+name+" "+errString(err))
+
+					return resp, err
+				}
+			}
+		}
+
+		ts, c := testServerAndClient(testUser, testPass, testHandlerFunc(http.StatusConflict, nil))
+		defer ts.Close()
+
+		Use(trace("a"), trace("b"))(c)
+
+		if _, err := c.RegisterClip(ctx, clip); !errors.Is(err, ErrAlreadyRegistered) {
+			t.Fatalf("err = %v, want %v", err, ErrAlreadyRegistered)
+		}
+
+		want := []string{"a>RegisterClip", "b>RegisterClip", "<b RegisterClip: already registered (conflict)", "<a RegisterClip: already registered (conflict)"}
+
+		if got := strings.Join(log, ", "); got != strings.Join(want, ", ") {
+			t.Fatalf("log = %s, want %s", got, strings.Join(want, ", "))
+		}
+	})
+
+	t.Run("rewrite", func(t *testing.T) {
+		var playURL string
+
+		ts, c := testServerAndClient(testUser, testPass, func(w http.ResponseWriter, r *http.Request) {
+			playURL = r.FormValue("PlayUrl")
+			testHandlerFunc(http.StatusOK, nil)(w, r)
+		})
+		defer ts.Close()
+
+		Use(func(next Handler) Handler {
+			return func(ctx context.Context, call Call) (*Response, error) {
+				if u := call.Params.Get("PlayUrl"); strings.HasPrefix(u, "http://") {
+					call.Params.Set("PlayUrl", "https://"+strings.TrimPrefix(u, "http://"))
+				}
+
+				return next(ctx, call)
+			}
+		})(c)
+
+		if _, err := c.RegisterClip(ctx, clip); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := playURL, "https://www.tv4play.se/play/TC"; got != want {
+			t.Fatalf("PlayUrl = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("test double", func(t *testing.T) {
+		var calls []Call
+
+		c := testClient(HTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			t.Fatalf("unexpected HTTP request to %s", r.URL)
+			return nil, nil
+		})}), Use(func(next Handler) Handler {
+			return func(ctx context.Context, call Call) (*Response, error) {
+				calls = append(calls, call)
+
+				return &Response{StatusCode: http.StatusOK}, nil
+			}
+		}))
+
+		episode := MakeEpisode("TC1", "SC", "Episode", 60, Date(2017, 3, 27), Webisode)
+
+		if _, err := c.UpdateEpisode(ctx, episode); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := len(calls), 1; got != want {
+			t.Fatalf("len(calls) = %d, want %d", got, want)
+		}
+
+		if got, want := calls[0].Endpoint, UpdateEpisodeEndpoint; got != want {
+			t.Fatalf("calls[0].Endpoint = %q, want %q", got, want)
+		}
+
+		if got, ok := calls[0].Request.(*Episode); !ok || *got != episode {
+			t.Fatalf("calls[0].Request = %#v, want %+v", calls[0].Request, episode)
+		}
+
+		if got, want := calls[0].Params.Get("TitleCode"), "TC1"; got != want {
+			t.Fatalf("calls[0].Params.Get(\"TitleCode\") = %q, want %q", got, want)
+		}
+
+		if _, ok := calls[0].Params["pass"]; ok {
+			t.Fatalf("calls[0].Params contains the password")
+		}
+	})
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,136 @@
+package titleservice
+
+// Options for MakeEpisode
+
+// WithDescription sets the Description of an Episode
+func WithDescription(description string) func(*Episode) {
+	return func(e *Episode) {
+		e.Description = description
+	}
+}
+
+// WithAvailableUntil sets the last date an Episode is available
+func WithAvailableUntil(date MMSDate) func(*Episode) {
+	return func(e *Episode) {
+		e.AvailableUntil = date
+	}
+}
+
+// WithPlayURL sets the PlayURL of an Episode
+func WithPlayURL(rawurl string) func(*Episode) {
+	return func(e *Episode) {
+		e.PlayURL = rawurl
+	}
+}
+
+// WithLive sets the Live TV broadcast fields of an Episode, obligatory for categories 1, 2, 3, 8
+func WithLive(title string, day MMSDate, time MMSTime, channel LiveChannelID) func(*Episode) {
+	return func(e *Episode) {
+		e.LiveTitle = title
+		e.LiveTvDay = day
+		e.LiveTime = time
+		e.LiveChannelID = channel
+	}
+}
+
+// WithBroadcast sets the Live TV broadcast fields of an Episode from b, see WithLive
+func WithBroadcast(b Broadcast) func(*Episode) {
+	return WithLive(b.Title, b.Day(), b.Time(), b.Channel)
+}
+
+// WithLiveTitle sets the LiveTitle of an Episode, e.g. when the title in the TV listing differs
+func WithLiveTitle(title string) func(*Episode) {
+	return func(e *Episode) {
+		e.LiveTitle = title
+	}
+}
+
+// WithEpisodeNumber sets the EpisodeNumber of an Episode, only applicable to categories 1, 2, 4, 5
+func WithEpisodeNumber(n int) func(*Episode) {
+	return func(e *Episode) {
+		e.EpisodeNumber = n
+	}
+}
+
+// WithLinkedTitleCode sets the LinkedTitleCode of an Episode, only applicable to categories 2, 3, 8
+func WithLinkedTitleCode(titleCode string) func(*Episode) {
+	return func(e *Episode) {
+		e.LinkedTitleCode = titleCode
+	}
+}
+
+// WithTargetGroup sets the TargetGroupCode of an Episode (Adults or Children)
+func WithTargetGroup(code TargetGroup) func(*Episode) {
+	return func(e *Episode) {
+		e.TargetGroupCode = code
+	}
+}
+
+// WithTerritory sets the TerritoryCode of an Episode (Swedish or Foreign)
+func WithTerritory(code Territory) func(*Episode) {
+	return func(e *Episode) {
+		e.TerritoryCode = code
+	}
+}
+
+// WithSuggestedGenres sets up to three suggested genres of an Episode.
+// Any previously suggested genres are cleared, and genres after the third are ignored.
+func WithSuggestedGenres(genres ...string) func(*Episode) {
+	return func(e *Episode) {
+		fields := []*string{&e.SuggestedGenre1, &e.SuggestedGenre2, &e.SuggestedGenre3}
+
+		for i, f := range fields {
+			*f = ""
+
+			if i < len(genres) {
+				*f = genres[i]
+			}
+		}
+	}
+}
+
+// Options for MakeClip
+
+// WithClipDescription sets the Description of a Clip
+func WithClipDescription(description string) func(*Clip) {
+	return func(c *Clip) {
+		c.Description = description
+	}
+}
+
+// WithClipAvailableUntil sets the last date a Clip is available
+func WithClipAvailableUntil(date MMSDate) func(*Clip) {
+	return func(c *Clip) {
+		c.AvailableUntil = date
+	}
+}
+
+// WithClipPlayURL sets the PlayURL of a Clip
+func WithClipPlayURL(rawurl string) func(*Clip) {
+	return func(c *Clip) {
+		c.PlayURL = rawurl
+	}
+}
+
+// Options for MakeSeries
+
+// WithSeriesDescription sets the Description of a Series
+func WithSeriesDescription(description string) func(*Series) {
+	return func(s *Series) {
+		s.Description = description
+	}
+}
+
+// WithSeasonNumber sets the SeasonNumber of a Series
+func WithSeasonNumber(n int) func(*Series) {
+	return func(s *Series) {
+		s.SeasonNumber = n
+	}
+}
+
+// WithGenreText sets the GenreText of a Series
+func WithGenreText(text string) func(*Series) {
+	return func(s *Series) {
+		s.GenreText = text
+	}
+}
@@ -0,0 +1,100 @@
+package titleservice
+
+import "testing"
+
+func TestEpisodeOptions(t *testing.T) {
+	e := MakeEpisode("TC", "SC", "T", 1, Date(2017, 3, 27), TvProgram,
+		WithDescription("Description"),
+		WithAvailableUntil(Date(2017, 4, 27)),
+		WithPlayURL("https://example.com/play"),
+		WithLive("Live title", Date(2017, 3, 26), BroadcastTime(20, 0), TV4),
+		WithEpisodeNumber(3),
+		WithTargetGroup(Children),
+		WithTerritory(Swedish),
+		WithSuggestedGenres("Drama", "Komedi"),
+	)
+
+	if err := e.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Episode{
+		TitleCode:       "TC",
+		SeriesCode:      "SC",
+		Title:           "T",
+		Length:          1,
+		PublishedAt:     Date(2017, 3, 27),
+		AvailableUntil:  Date(2017, 4, 27),
+		CategoryID:      TvProgram,
+		EpisodeNumber:   3,
+		Description:     "Description",
+		LiveTitle:       "Live title",
+		LiveTvDay:       Date(2017, 3, 26),
+		LiveTime:        BroadcastTime(20, 0),
+		LiveChannelID:   TV4,
+		PlayURL:         "https://example.com/play",
+		TargetGroupCode: Children,
+		TerritoryCode:   Swedish,
+		SuggestedGenre1: "Drama",
+		SuggestedGenre2: "Komedi",
+	}
+
+	if e != want {
+		t.Fatalf("e = %+v, want %+v", e, want)
+	}
+
+	e = MakeEpisode("TC", "SC", "T", 1, Date(2017, 3, 27), TvSegment,
+		WithSuggestedGenres("A", "B", "C", "D"),
+		WithLinkedTitleCode("LTC"),
+	)
+
+	if got, want := e.SuggestedGenre3, "C"; got != want {
+		t.Fatalf("e.SuggestedGenre3 = %q, want %q", got, want)
+	}
+
+	if got, want := e.LinkedTitleCode, "LTC"; got != want {
+		t.Fatalf("e.LinkedTitleCode = %q, want %q", got, want)
+	}
+}
+
+func TestClipOptions(t *testing.T) {
+	c := MakeClip("TC", "T", 1, Date(2017, 3, 27),
+		WithClipDescription("Description"),
+		WithClipAvailableUntil(Date(2017, 4, 27)),
+		WithClipPlayURL("https://example.com/play"),
+	)
+
+	want := Clip{
+		TitleCode:      "TC",
+		Title:          "T",
+		Length:         1,
+		PublishedAt:    Date(2017, 3, 27),
+		AvailableUntil: Date(2017, 4, 27),
+		Description:    "Description",
+		PlayURL:        "https://example.com/play",
+	}
+
+	if c != want {
+		t.Fatalf("c = %+v, want %+v", c, want)
+	}
+}
+
+func TestSeriesOptions(t *testing.T) {
+	s := MakeSeries("SC", "T",
+		WithSeriesDescription("Description"),
+		WithSeasonNumber(2),
+		WithGenreText("Drama"),
+	)
+
+	want := Series{
+		SeriesCode:   "SC",
+		Title:        "T",
+		SeasonNumber: 2,
+		Description:  "Description",
+		GenreText:    "Drama",
+	}
+
+	if s != want {
+		t.Fatalf("s = %+v, want %+v", s, want)
+	}
+}
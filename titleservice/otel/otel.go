@@ -0,0 +1,128 @@
+/*
+
+Package otel traces the calls sent by a titleservice.Client using OpenTelemetry
+
+	c := titleservice.NewClient(username, password,
+		titleservice.Trace(otel.NewTracer()),
+	)
+
+Every call gets a client span named after its endpoint, e.g. "titleservice RegisterEpisode",
+with the attributes
+
+	titleservice.endpoint
+	titleservice.title_code
+	titleservice.series_code
+	titleservice.category_id (episodes only)
+	titleservice.simulate
+	http.response.status_code
+
+The span context is propagated to the MMS TitleService API in the headers of each request.
+
+*/
+package otel
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/TV4/mms/titleservice"
+)
+
+const instrumentationName = "github.com/TV4/mms/titleservice/otel"
+
+// Tracer is a titleservice.Tracer backed by OpenTelemetry
+type Tracer struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+	tracer     trace.Tracer
+}
+
+// TracerProvider changes the TracerProvider used, which defaults to the global one
+func TracerProvider(tp trace.TracerProvider) func(*Tracer) {
+	return func(t *Tracer) {
+		t.provider = tp
+	}
+}
+
+// Propagator changes the TextMapPropagator used, which defaults to the global one
+func Propagator(p propagation.TextMapPropagator) func(*Tracer) {
+	return func(t *Tracer) {
+		t.propagator = p
+	}
+}
+
+// NewTracer creates a Tracer
+func NewTracer(options ...func(*Tracer)) *Tracer {
+	t := &Tracer{
+		provider:   otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+
+	for _, f := range options {
+		f(t)
+	}
+
+	t.tracer = t.provider.Tracer(instrumentationName)
+
+	return t
+}
+
+// Start implements titleservice.Tracer
+func (t *Tracer) Start(ctx context.Context, call titleservice.Call) (context.Context, titleservice.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("titleservice.endpoint", string(call.Endpoint)),
+		attribute.Bool("titleservice.simulate", call.Simulate),
+	}
+
+	if code := call.Params.Get("TitleCode"); code != "" {
+		attrs = append(attrs, attribute.String("titleservice.title_code", code))
+	}
+
+	if code := call.Params.Get("SeriesCode"); code != "" {
+		attrs = append(attrs, attribute.String("titleservice.series_code", code))
+	}
+
+	if e, ok := call.Request.(*titleservice.Episode); ok {
+		attrs = append(attrs, attribute.Int("titleservice.category_id", int(e.CategoryID)))
+	}
+
+	ctx, s := t.tracer.Start(ctx, "titleservice "+string(call.Endpoint),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	return ctx, &span{s}
+}
+
+// Inject implements titleservice.Tracer
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+type span struct {
+	s trace.Span
+}
+
+// SetStatusCode sets the status code attribute, and adds an event for every response
+// as a call may receive several when retried
+func (s *span) SetStatusCode(code int) {
+	s.s.SetAttributes(semconv.HTTPResponseStatusCode(code))
+	s.s.AddEvent("response", trace.WithAttributes(semconv.HTTPResponseStatusCode(code)))
+}
+
+// End the span, recording err if any
+func (s *span) End(err error) {
+	if err != nil {
+		s.s.RecordError(err)
+		s.s.SetStatus(codes.Error, err.Error())
+	}
+
+	s.s.End()
+}
@@ -0,0 +1,125 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/TV4/mms/titleservice"
+	"github.com/TV4/mms/titleservice/titleservicetest"
+)
+
+func TestTracer(t *testing.T) {
+	ctx := context.Background()
+
+	exporter := tracetest.NewInMemoryExporter()
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(ctx)
+
+	ts := titleservicetest.NewServer("user", "pass")
+	defer ts.Close()
+
+	var traceparents []string
+
+	// forward to the fake server, recording the propagated trace context
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("Traceparent"))
+		ts.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	c := ts.Client(
+		titleservice.BaseURL(proxy.URL),
+		titleservice.Simulate(true),
+		titleservice.Retry(titleservice.ExponentialBackoff{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+		titleservice.Trace(NewTracer(TracerProvider(tp), Propagator(propagation.TraceContext{}))),
+	)
+
+	ts.Fail(titleservice.RegisterEpisodeEndpoint, http.StatusServiceUnavailable)
+
+	episode := titleservice.MakeEpisode("TC1", "SC", "Episode", 60, titleservice.Date(2017, 3, 27), titleservice.Webisode)
+
+	if _, err := c.RegisterEpisode(ctx, episode); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := c.RegisterSeries(ctx, titleservice.Series{SeriesCode: "SC"}); err == nil {
+		t.Fatalf("expected error for a series without a title")
+	}
+
+	_, err := c.UpdateClip(ctx, titleservice.MakeClip("TC2", "Clip", 30, titleservice.Date(2017, 3, 27)))
+	if err == nil {
+		t.Fatalf("expected error updating a clip that is not registered")
+	}
+
+	spans := exporter.GetSpans()
+
+	if got, want := len(spans), 2; got != want {
+		t.Fatalf("len(spans) = %d, want %d", got, want)
+	}
+
+	register, update := spans[0], spans[1]
+
+	if got, want := register.Name, "titleservice RegisterEpisode"; got != want {
+		t.Fatalf("register.Name = %q, want %q", got, want)
+	}
+
+	if got, want := register.SpanKind, trace.SpanKindClient; got != want {
+		t.Fatalf("register.SpanKind = %v, want %v", got, want)
+	}
+
+	attrs := attribute.NewSet(register.Attributes...)
+
+	for key, want := range map[attribute.Key]attribute.Value{
+		"titleservice.endpoint":     attribute.StringValue("RegisterEpisode"),
+		"titleservice.title_code":   attribute.StringValue("TC1"),
+		"titleservice.series_code":  attribute.StringValue("SC"),
+		"titleservice.category_id":  attribute.IntValue(4),
+		"titleservice.simulate":     attribute.BoolValue(true),
+		"http.response.status_code": attribute.IntValue(200),
+	} {
+		if got, ok := attrs.Value(key); !ok || got != want {
+			t.Fatalf("attribute %s = %v, want %v", key, got.Emit(), want.Emit())
+		}
+	}
+
+	if got, want := len(register.Events), 2; got != want {
+		t.Fatalf("len(register.Events) = %d, want %d (one per response)", got, want)
+	}
+
+	if got, want := register.Status.Code, codes.Unset; got != want {
+		t.Fatalf("register.Status.Code = %v, want %v", got, want)
+	}
+
+	if got, want := update.Status.Code, codes.Error; got != want {
+		t.Fatalf("update.Status.Code = %v, want %v", got, want)
+	}
+
+	updateAttrs := attribute.NewSet(update.Attributes...)
+
+	if code, _ := updateAttrs.Value("http.response.status_code"); code.AsInt64() != 404 {
+		t.Fatalf("update status code = %v, want 404", code.Emit())
+	}
+
+	if got, want := len(traceparents), 3; got != want {
+		t.Fatalf("len(traceparents) = %d, want %d", got, want)
+	}
+
+	want := "00-" + register.SpanContext.TraceID().String() + "-" + register.SpanContext.SpanID().String() + "-01"
+
+	for i, got := range traceparents[:2] {
+		if got != want {
+			t.Fatalf("traceparents[%d] = %q, want %q", i, got, want)
+		}
+	}
+}
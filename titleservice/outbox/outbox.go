@@ -0,0 +1,654 @@
+/*
+
+Package outbox provides a durable queue in front of the Register methods of a titleservice.Client
+
+Requests are appended to a log file before Enqueue returns, and delivered in the
+background by Run. The outcome of every delivery is appended to the same file, so
+pending requests are resumed when the outbox is opened again after a restart.
+
+	o, err := outbox.Open("titleservice.outbox", c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer o.Close()
+
+	go o.Run(ctx)
+
+	id, err := o.RegisterEpisode(episode)
+
+*/
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TV4/mms/titleservice"
+)
+
+// ErrRunning is returned by Run and Flush if the outbox is already being delivered
+var ErrRunning = errors.New("outbox: already running")
+
+// DefaultRetryPolicy makes at most 10 deliveries of each request, waiting up to 10 minutes between them
+var DefaultRetryPolicy = titleservice.ExponentialBackoff{
+	MaxAttempts: 10,
+	BaseDelay:   5 * time.Second,
+	MaxDelay:    10 * time.Minute,
+}
+
+// State of an item in the outbox
+type State string
+
+// States
+const (
+	Pending    State = "pending"
+	Registered State = "registered"
+	Conflict   State = "conflict"
+	Failed     State = "failed"
+)
+
+// Item is a request in the outbox
+type Item struct {
+	ID       string
+	Request  titleservice.Request // *titleservice.Series, *titleservice.Episode or *titleservice.Clip
+	Enqueued time.Time
+	State    State
+	Attempts int                    // number of deliveries made
+	Next     time.Time              // earliest time of the next delivery of a pending item
+	Err      string                 // error of the last delivery, if any
+	Response *titleservice.Response // response to the last delivery, if any
+}
+
+// Outbox is a durable queue of requests to the MMS TitleService API
+type Outbox struct {
+	client *titleservice.Client
+	retry  titleservice.RetryPolicy
+	onDone func(Item)
+	now    func() time.Time
+
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	items   map[string]*Item
+	pending []string // ids of the pending items, in the order they were enqueued
+	running bool
+	notify  chan struct{}
+}
+
+// Retry changes the RetryPolicy deciding when a failed delivery is retried.
+// This is in addition to any retries made by the client itself.
+func Retry(policy titleservice.RetryPolicy) func(*Outbox) {
+	return func(o *Outbox) {
+		o.retry = policy
+	}
+}
+
+// OnDone sets a func called with every item reaching a terminal state
+func OnDone(f func(Item)) func(*Outbox) {
+	return func(o *Outbox) {
+		o.onDone = f
+	}
+}
+
+// Open the outbox in the file at path, creating it if it does not exist.
+// The requests are delivered using c.
+func Open(path string, c *titleservice.Client, options ...func(*Outbox)) (*Outbox, error) {
+	o := &Outbox{
+		client: c,
+		retry:  DefaultRetryPolicy,
+		now:    time.Now,
+		path:   path,
+		items:  map[string]*Item{},
+		notify: make(chan struct{}, 1),
+	}
+
+	for _, f := range options {
+		f(o)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := o.load(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	o.file = f
+
+	return o, nil
+}
+
+// Close the file of the outbox. Pending items are delivered when it is opened again.
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.file.Close()
+}
+
+// RegisterSeries enqueues a Series, returning the id of its item
+func (o *Outbox) RegisterSeries(series titleservice.Series) (string, error) {
+	return o.Enqueue(&series)
+}
+
+// RegisterEpisode enqueues an Episode, returning the id of its item
+func (o *Outbox) RegisterEpisode(episode titleservice.Episode) (string, error) {
+	return o.Enqueue(&episode)
+}
+
+// RegisterClip enqueues a Clip, returning the id of its item
+func (o *Outbox) RegisterClip(clip titleservice.Clip) (string, error) {
+	return o.Enqueue(&clip)
+}
+
+// Enqueue a *titleservice.Series, *titleservice.Episode or *titleservice.Clip, returning
+// the id of its item. The request is validated, and written to the file before returning.
+func (o *Outbox) Enqueue(req titleservice.Request) (string, error) {
+	kind, err := kindOf(req)
+	if err != nil {
+		return "", err
+	}
+
+	if err := req.(interface{ Validate() error }).Validate(); err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	e := entry{Op: opEnqueue, ID: id, Time: o.now(), Kind: kind, Request: b}
+
+	if err := o.write(e); err != nil {
+		return "", err
+	}
+
+	if err := o.apply(e); err != nil {
+		return "", err
+	}
+
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+
+	return id, nil
+}
+
+// Item returns the item with the id
+func (o *Outbox) Item(id string) (Item, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	item, ok := o.items[id]
+	if !ok {
+		return Item{}, false
+	}
+
+	return *item, true
+}
+
+// Pending returns the pending items, in the order they were enqueued
+func (o *Outbox) Pending() []Item {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	items := make([]Item, 0, len(o.pending))
+
+	for _, id := range o.pending {
+		items = append(items, *o.items[id])
+	}
+
+	return items
+}
+
+// Run delivers pending items, including those enqueued while running, until ctx is done.
+// It returns the error from ctx, or the error writing the outcome of a delivery to the file.
+func (o *Outbox) Run(ctx context.Context) error {
+	return o.run(ctx, false)
+}
+
+// Flush delivers pending items until there are none left, waiting for
+// items to be retried. It returns early with the error from ctx if ctx is done.
+func (o *Outbox) Flush(ctx context.Context) error {
+	return o.run(ctx, true)
+}
+
+func (o *Outbox) run(ctx context.Context, flush bool) error {
+	o.mu.Lock()
+
+	if o.running {
+		o.mu.Unlock()
+		return ErrRunning
+	}
+
+	o.running = true
+	o.mu.Unlock()
+
+	defer func() {
+		o.mu.Lock()
+		o.running = false
+		o.mu.Unlock()
+	}()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		item, wait, ok := o.next()
+
+		if !ok && flush {
+			return nil
+		}
+
+		if item != nil {
+			if err := o.deliver(ctx, *item); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var (
+			timer *time.Timer
+			retry <-chan time.Time
+		)
+
+		if ok {
+			timer = time.NewTimer(wait)
+			retry = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-o.notify:
+		case <-retry:
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// next returns the first pending item due for delivery. If no item is due, it returns
+// the time until the next one is. It returns false if there are no pending items.
+func (o *Outbox) next() (*Item, time.Duration, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.pending) == 0 {
+		return nil, 0, false
+	}
+
+	now := o.now()
+
+	var wait time.Duration
+
+	for i, id := range o.pending {
+		item := o.items[id]
+
+		if !item.Next.After(now) {
+			due := *item
+			return &due, 0, true
+		}
+
+		if d := item.Next.Sub(now); i == 0 || d < wait {
+			wait = d
+		}
+	}
+
+	return nil, wait, true
+}
+
+// deliver the item, and write the outcome to the file
+func (o *Outbox) deliver(ctx context.Context, item Item) error {
+	resp, err := register(ctx, o.client, item.Request)
+
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	e := entry{
+		Op:       opDone,
+		ID:       item.ID,
+		Time:     o.now(),
+		Attempts: item.Attempts + 1,
+		Response: resp,
+	}
+
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	switch {
+	case err == nil:
+		e.State = Registered
+	case errors.Is(err, titleservice.ErrAlreadyRegistered):
+		e.State = Conflict
+	case retryable(err):
+		e.State = Failed
+
+		if delay, ok := o.retry.Backoff(item.Request.Endpoint(), e.Attempts); ok {
+			var apiErr *titleservice.APIError
+
+			if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+				delay = apiErr.RetryAfter
+			}
+
+			e.Op, e.State, e.Next = opAttempt, Pending, e.Time.Add(delay)
+		}
+	default:
+		e.State = Failed
+	}
+
+	o.mu.Lock()
+
+	if err := o.write(e); err != nil {
+		o.mu.Unlock()
+		return err
+	}
+
+	if err := o.apply(e); err != nil {
+		o.mu.Unlock()
+		return err
+	}
+
+	done := *o.items[item.ID]
+
+	o.mu.Unlock()
+
+	if e.State != Pending && o.onDone != nil {
+		o.onDone(done)
+	}
+
+	return nil
+}
+
+// Compact rewrites the file, keeping only the pending items
+func (o *Outbox) Compact() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var buf bytes.Buffer
+
+	for _, id := range o.pending {
+		item := o.items[id]
+
+		kind, _ := kindOf(item.Request)
+
+		b, err := json.Marshal(item.Request)
+		if err != nil {
+			return err
+		}
+
+		entries := []entry{{Op: opEnqueue, ID: id, Time: item.Enqueued, Kind: kind, Request: b}}
+
+		if item.Attempts > 0 {
+			entries = append(entries, entry{
+				Op:       opAttempt,
+				ID:       id,
+				Time:     item.Enqueued,
+				State:    Pending,
+				Attempts: item.Attempts,
+				Next:     item.Next,
+				Error:    item.Err,
+				Response: item.Response,
+			})
+		}
+
+		for _, e := range entries {
+			if err := encode(&buf, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	tmp := o.path + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := os.Rename(tmp, o.path); err != nil {
+		f.Close()
+		return err
+	}
+
+	o.file.Close()
+	o.file = f
+
+	for id, item := range o.items {
+		if item.State != Pending {
+			delete(o.items, id)
+		}
+	}
+
+	return nil
+}
+
+// Ops of the entries in the file
+const (
+	opEnqueue = "enqueue"
+	opAttempt = "attempt"
+	opDone    = "done"
+)
+
+// entry is a line in the file
+type entry struct {
+	Op       string                 `json:"op"`
+	ID       string                 `json:"id"`
+	Time     time.Time              `json:"time"`
+	Kind     string                 `json:"kind,omitempty"`
+	Request  json.RawMessage        `json:"request,omitempty"`
+	State    State                  `json:"state,omitempty"`
+	Attempts int                    `json:"attempts,omitempty"`
+	Next     time.Time              `json:"next"`
+	Error    string                 `json:"error,omitempty"`
+	Response *titleservice.Response `json:"response,omitempty"`
+}
+
+func encode(w io.Writer, e entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(b, '\n'))
+
+	return err
+}
+
+// write the entry to the end of the file, and sync it to disk
+func (o *Outbox) write(e entry) error {
+	if err := encode(o.file, e); err != nil {
+		return err
+	}
+
+	return o.file.Sync()
+}
+
+// load the entries in the file. A last line that was only partially
+// written, e.g. due to a crash, is truncated.
+func (o *Outbox) load(f *os.File) error {
+	r := bufio.NewReader(f)
+
+	var offset int64
+
+	for line := 1; ; line++ {
+		b, err := r.ReadBytes('\n')
+
+		if err == io.EOF {
+			if len(b) > 0 {
+				if err := f.Truncate(offset); err != nil {
+					return err
+				}
+			}
+
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		var e entry
+
+		if err := json.Unmarshal(b, &e); err != nil {
+			return fmt.Errorf("outbox: %s:%d: %v", o.path, line, err)
+		}
+
+		if err := o.apply(e); err != nil {
+			return fmt.Errorf("outbox: %s:%d: %v", o.path, line, err)
+		}
+
+		offset += int64(len(b))
+	}
+
+	_, err := f.Seek(offset, io.SeekStart)
+
+	return err
+}
+
+// apply the entry to the items
+func (o *Outbox) apply(e entry) error {
+	if e.Op == opEnqueue {
+		req, err := newRequest(e.Kind)
+		if err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal(e.Request, req); err != nil {
+			return err
+		}
+
+		o.items[e.ID] = &Item{ID: e.ID, Request: req, Enqueued: e.Time, State: Pending}
+		o.pending = append(o.pending, e.ID)
+
+		return nil
+	}
+
+	item, ok := o.items[e.ID]
+	if !ok {
+		return fmt.Errorf("unknown item %q", e.ID)
+	}
+
+	switch e.Op {
+	case opAttempt, opDone:
+		item.State = e.State
+		item.Attempts = e.Attempts
+		item.Next = e.Next
+		item.Err = e.Error
+		item.Response = e.Response
+	default:
+		return fmt.Errorf("unknown op %q", e.Op)
+	}
+
+	if item.State != Pending {
+		for i, id := range o.pending {
+			if id == e.ID {
+				o.pending = append(o.pending[:i], o.pending[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func kindOf(req titleservice.Request) (string, error) {
+	switch req.(type) {
+	case *titleservice.Series:
+		return "series", nil
+	case *titleservice.Episode:
+		return "episode", nil
+	case *titleservice.Clip:
+		return "clip", nil
+	}
+
+	return "", fmt.Errorf("outbox: unsupported request %T", req)
+}
+
+func newRequest(kind string) (titleservice.Request, error) {
+	switch kind {
+	case "series":
+		return &titleservice.Series{}, nil
+	case "episode":
+		return &titleservice.Episode{}, nil
+	case "clip":
+		return &titleservice.Clip{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown kind %q", kind)
+}
+
+func register(ctx context.Context, c *titleservice.Client, req titleservice.Request) (*titleservice.Response, error) {
+	switch r := req.(type) {
+	case *titleservice.Series:
+		return c.RegisterSeries(ctx, *r)
+	case *titleservice.Episode:
+		return c.RegisterEpisode(ctx, *r)
+	case *titleservice.Clip:
+		return c.RegisterClip(ctx, *r)
+	}
+
+	return nil, fmt.Errorf("outbox: unsupported request %T", req)
+}
+
+// retryable reports whether a delivery that failed with err is worth retrying
+func retryable(err error) bool {
+	var apiErr *titleservice.APIError
+
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+
+	var urlErr *url.Error
+
+	return errors.As(err, &urlErr)
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
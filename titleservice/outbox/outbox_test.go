@@ -0,0 +1,345 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TV4/mms/titleservice"
+	"github.com/TV4/mms/titleservice/titleservicetest"
+)
+
+var testRetryPolicy = titleservice.ExponentialBackoff{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    5 * time.Millisecond,
+}
+
+func open(t *testing.T, path string, c *titleservice.Client, options ...func(*Outbox)) *Outbox {
+	t.Helper()
+
+	o, err := Open(path, c, append([]func(*Outbox){Retry(testRetryPolicy)}, options...)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return o
+}
+
+func flush(t *testing.T, o *Outbox) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := o.Flush(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	series := titleservice.MakeSeries("SC", "Series")
+	clip := titleservice.MakeClip("TC", "Clip", 30, titleservice.Date(2017, 3, 27))
+
+	t.Run("outcomes", func(t *testing.T) {
+		ts := titleservicetest.NewServer("user", "pass")
+		defer ts.Close()
+
+		o := open(t, filepath.Join(t.TempDir(), "outbox"), ts.Client())
+		defer o.Close()
+
+		ts.Fail(titleservice.RegisterSeriesEndpoint, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+		ts.Fail(titleservice.RegisterClipEndpoint, http.StatusBadRequest)
+
+		seriesID, err := o.RegisterSeries(series)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		clipID, err := o.RegisterClip(clip)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := len(o.Pending()), 2; got != want {
+			t.Fatalf("len(o.Pending()) = %d, want %d", got, want)
+		}
+
+		flush(t, o)
+
+		conflictID, err := o.RegisterSeries(series)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		flush(t, o)
+
+		for _, tt := range []struct {
+			id       string
+			state    State
+			attempts int
+		}{
+			{seriesID, Registered, 3},
+			{clipID, Failed, 1},
+			{conflictID, Conflict, 1},
+		} {
+			item, ok := o.Item(tt.id)
+			if !ok {
+				t.Fatalf("o.Item(%q) not found", tt.id)
+			}
+
+			if item.State != tt.state || item.Attempts != tt.attempts {
+				t.Fatalf("item = %s after %d attempts, want %s after %d", item.State, item.Attempts, tt.state, tt.attempts)
+			}
+		}
+
+		if got, want := len(ts.Series()), 1; got != want {
+			t.Fatalf("len(ts.Series()) = %d, want %d", got, want)
+		}
+
+		if got, want := len(o.Pending()), 0; got != want {
+			t.Fatalf("len(o.Pending()) = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		ts := titleservicetest.NewServer("user", "pass")
+		defer ts.Close()
+
+		o := open(t, filepath.Join(t.TempDir(), "outbox"), ts.Client())
+		defer o.Close()
+
+		ts.Fail(titleservice.RegisterClipEndpoint, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+
+		id, err := o.RegisterClip(clip)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		flush(t, o)
+
+		item, _ := o.Item(id)
+
+		if got, want := item.State, Failed; got != want {
+			t.Fatalf("item.State = %q, want %q", got, want)
+		}
+
+		if got, want := item.Attempts, 3; got != want {
+			t.Fatalf("item.Attempts = %d, want %d", got, want)
+		}
+
+		if !strings.Contains(item.Err, titleservice.ErrServiceUnavailable.Error()) {
+			t.Fatalf("item.Err = %q, want %q", item.Err, titleservice.ErrServiceUnavailable)
+		}
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		ts := titleservicetest.NewServer("user", "pass")
+		defer ts.Close()
+
+		path := filepath.Join(t.TempDir(), "outbox")
+
+		o := open(t, path, ts.Client())
+
+		seriesID, _ := o.RegisterSeries(series)
+		clipID, _ := o.RegisterClip(clip)
+
+		if err := o.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		o = open(t, path, ts.Client())
+
+		pending := o.Pending()
+
+		if got, want := len(pending), 2; got != want {
+			t.Fatalf("len(pending) = %d, want %d", got, want)
+		}
+
+		if got, want := pending[0].ID, seriesID; got != want {
+			t.Fatalf("pending[0].ID = %q, want %q", got, want)
+		}
+
+		if got, want := *pending[1].Request.(*titleservice.Clip), clip; got != want {
+			t.Fatalf("pending[1].Request = %+v, want %+v", got, want)
+		}
+
+		flush(t, o)
+		o.Close()
+
+		o = open(t, path, ts.Client())
+		defer o.Close()
+
+		if got, want := len(o.Pending()), 0; got != want {
+			t.Fatalf("len(o.Pending()) = %d, want %d", got, want)
+		}
+
+		if item, _ := o.Item(clipID); item.State != Registered {
+			t.Fatalf("item.State = %q, want %q", item.State, Registered)
+		}
+
+		if got, want := len(ts.Requests()), 2; got != want {
+			t.Fatalf("len(ts.Requests()) = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("partially written line", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "outbox")
+
+		o := open(t, path, nil)
+
+		id, _ := o.RegisterSeries(series)
+
+		o.Close()
+
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		f.WriteString(`{"op":"enqueue","id":"abc","ti`)
+		f.Close()
+
+		o = open(t, path, nil)
+
+		if _, err := o.RegisterClip(clip); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		o.Close()
+
+		o = open(t, path, nil)
+		defer o.Close()
+
+		pending := o.Pending()
+
+		if got, want := len(pending), 2; got != want {
+			t.Fatalf("len(pending) = %d, want %d", got, want)
+		}
+
+		if got, want := pending[0].ID, id; got != want {
+			t.Fatalf("pending[0].ID = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("corrupt file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "outbox")
+
+		if err := os.WriteFile(path, []byte("{\"op\":\"done\",\"id\":\"abc\"}\n"), 0600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := Open(path, nil); err == nil || !strings.Contains(err.Error(), `outbox:1: unknown item "abc"`) {
+			t.Fatalf("err = %v, want unknown item", err)
+		}
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "outbox")
+
+		o := open(t, path, nil)
+		defer o.Close()
+
+		_, err := o.RegisterSeries(titleservice.MakeSeries("SC", ""))
+
+		if !errors.Is(err, titleservice.ErrMissingParameter) {
+			t.Fatalf("err = %v, want ErrMissingParameter", err)
+		}
+
+		if got, want := len(o.Pending()), 0; got != want {
+			t.Fatalf("len(o.Pending()) = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("run", func(t *testing.T) {
+		ts := titleservicetest.NewServer("user", "pass")
+		defer ts.Close()
+
+		done := make(chan Item, 1)
+
+		o := open(t, filepath.Join(t.TempDir(), "outbox"), ts.Client(), OnDone(func(item Item) {
+			done <- item
+		}))
+		defer o.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		errs := make(chan error, 1)
+
+		go func() {
+			errs <- o.Run(ctx)
+		}()
+
+		id, err := o.RegisterSeries(series)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		select {
+		case item := <-done:
+			if item.ID != id || item.State != Registered {
+				t.Fatalf("item = %s %s, want %s %s", item.ID, item.State, id, Registered)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the item to be delivered")
+		}
+
+		if err := o.Flush(ctx); err != ErrRunning {
+			t.Fatalf("err = %v, want ErrRunning", err)
+		}
+
+		cancel()
+
+		if err := <-errs; err != context.Canceled {
+			t.Fatalf("err = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		ts := titleservicetest.NewServer("user", "pass")
+		defer ts.Close()
+
+		path := filepath.Join(t.TempDir(), "outbox")
+
+		o := open(t, path, ts.Client())
+
+		o.RegisterSeries(series)
+		flush(t, o)
+
+		id, _ := o.RegisterClip(clip)
+
+		if err := o.Compact(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		o.RegisterSeries(titleservice.MakeSeries("SC2", "Series 2"))
+		o.Close()
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := strings.Count(string(b), "\n"), 2; got != want {
+			t.Fatalf("lines = %d, want %d", got, want)
+		}
+
+		o = open(t, path, ts.Client())
+		defer o.Close()
+
+		pending := o.Pending()
+
+		if got, want := len(pending), 2; got != want {
+			t.Fatalf("len(pending) = %d, want %d", got, want)
+		}
+
+		if got, want := pending[0].ID, id; got != want {
+			t.Fatalf("pending[0].ID = %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+/*
+
+Package prometheus reports the requests sent by a titleservice.Client as Prometheus metrics
+
+	m, err := prometheus.New(prom.DefaultRegisterer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	c := titleservice.NewClient(username, password, titleservice.Instrument(m))
+
+The metrics are
+
+	titleservice_requests_total{endpoint, outcome}      counter
+	titleservice_request_duration_seconds{endpoint}     histogram
+	titleservice_retries_total{endpoint}                counter
+	titleservice_requests_in_flight{endpoint}           gauge
+
+*/
+package prometheus
+
+import (
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/TV4/mms/titleservice"
+)
+
+// Metrics is a titleservice.Metrics backed by Prometheus collectors
+type Metrics struct {
+	requests *prom.CounterVec
+	duration *prom.HistogramVec
+	retries  *prom.CounterVec
+	inFlight *prom.GaugeVec
+}
+
+// New creates Metrics and registers its collectors with reg
+func New(reg prom.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: "titleservice",
+			Name:      "requests_total",
+			Help:      "Requests sent to the MMS TitleService API, by endpoint and outcome.",
+		}, []string{"endpoint", "outcome"}),
+		duration: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: "titleservice",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of requests sent to the MMS TitleService API, by endpoint.",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"endpoint"}),
+		retries: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: "titleservice",
+			Name:      "retries_total",
+			Help:      "Requests sent again to the MMS TitleService API after failing, by endpoint.",
+		}, []string{"endpoint"}),
+		inFlight: prom.NewGaugeVec(prom.GaugeOpts{
+			Namespace: "titleservice",
+			Name:      "requests_in_flight",
+			Help:      "Requests to the MMS TitleService API waiting for a response, by endpoint.",
+		}, []string{"endpoint"}),
+	}
+
+	for _, c := range []prom.Collector{m.requests, m.duration, m.retries, m.inFlight} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// RequestStarted implements titleservice.Metrics
+func (m *Metrics) RequestStarted(endpoint titleservice.Endpoint) {
+	m.inFlight.WithLabelValues(string(endpoint)).Inc()
+}
+
+// RequestFinished implements titleservice.Metrics
+func (m *Metrics) RequestFinished(endpoint titleservice.Endpoint, outcome titleservice.Outcome, d time.Duration) {
+	m.inFlight.WithLabelValues(string(endpoint)).Dec()
+	m.requests.WithLabelValues(string(endpoint), string(outcome)).Inc()
+	m.duration.WithLabelValues(string(endpoint)).Observe(d.Seconds())
+}
+
+// RequestRetried implements titleservice.Metrics
+func (m *Metrics) RequestRetried(endpoint titleservice.Endpoint) {
+	m.retries.WithLabelValues(string(endpoint)).Inc()
+}
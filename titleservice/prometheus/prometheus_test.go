@@ -0,0 +1,70 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/TV4/mms/titleservice"
+	"github.com/TV4/mms/titleservice/titleservicetest"
+)
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	reg := prom.NewRegistry()
+
+	m, err := New(reg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts := titleservicetest.NewServer("user", "pass")
+	defer ts.Close()
+
+	c := ts.Client(
+		titleservice.Instrument(m),
+		titleservice.Retry(titleservice.ExponentialBackoff{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+	)
+
+	ts.Fail(titleservice.RegisterSeriesEndpoint, http.StatusServiceUnavailable)
+
+	series := titleservice.MakeSeries("SC", "Series")
+
+	for i := 0; i < 2; i++ {
+		c.RegisterSeries(ctx, series)
+	}
+
+	want := `
+# HELP titleservice_requests_total Requests sent to the MMS TitleService API, by endpoint and outcome.
+# TYPE titleservice_requests_total counter
+titleservice_requests_total{endpoint="RegisterSeries",outcome="conflict"} 1
+titleservice_requests_total{endpoint="RegisterSeries",outcome="ok"} 1
+titleservice_requests_total{endpoint="RegisterSeries",outcome="server_error"} 1
+# HELP titleservice_retries_total Requests sent again to the MMS TitleService API after failing, by endpoint.
+# TYPE titleservice_retries_total counter
+titleservice_retries_total{endpoint="RegisterSeries"} 1
+# HELP titleservice_requests_in_flight Requests to the MMS TitleService API waiting for a response, by endpoint.
+# TYPE titleservice_requests_in_flight gauge
+titleservice_requests_in_flight{endpoint="RegisterSeries"} 0
+`
+
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want),
+		"titleservice_requests_total", "titleservice_retries_total", "titleservice_requests_in_flight",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := testutil.CollectAndCount(m.duration), 1; got != want {
+		t.Fatalf("CollectAndCount(m.duration) = %d, want %d", got, want)
+	}
+
+	if _, err := New(reg); err == nil {
+		t.Fatalf("expected error registering the collectors twice")
+	}
+}
@@ -0,0 +1,107 @@
+package titleservice
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit configures the client to send at most rps requests per second,
+// allowing bursts of up to burst requests. The limit is shared by all calls
+// made using the client, including retries.
+func RateLimit(rps float64, burst int) func(*Client) {
+	return func(c *Client) {
+		if rps <= 0 {
+			c.limiter = nil
+			return
+		}
+
+		c.limiter = newTokenBucket(rps, burst)
+	}
+}
+
+// tokenBucket is a rate limiter holding up to burst tokens, refilled at rate tokens per second
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// Wait blocks until a token is available, or ctx is done
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	delay := b.reserve()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		b.cancel()
+
+		return newErrorWithMessage(context.DeadlineExceeded, "rate limit wait would exceed the deadline")
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		b.cancel()
+
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// reserve takes a token, returning how long to wait before it may be used
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	b.last = now
+
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel gives back a token taken by reserve that was never used
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
@@ -0,0 +1,84 @@
+package titleservice
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Run("option", func(t *testing.T) {
+		if c := NewClient("", "", RateLimit(10, 5)); c.limiter == nil {
+			t.Fatalf("c.limiter = nil, want limiter")
+		}
+
+		if c := NewClient("", "", RateLimit(0, 5)); c.limiter != nil {
+			t.Fatalf("c.limiter = %v, want nil", c.limiter)
+		}
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		ts, c := testServerAndClient(testUser, testPass, testHandlerFunc(http.StatusOK, nil))
+		defer ts.Close()
+
+		RateLimit(50, 1)(c)
+
+		start := time.Now()
+
+		for i := 0; i < 3; i++ {
+			if _, err := c.RegisterSeries(context.Background(), MakeSeries("series-code", "series-title")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if elapsed, want := time.Since(start), 35*time.Millisecond; elapsed < want {
+			t.Fatalf("elapsed = %v, want at least %v", elapsed, want)
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ts, c := testServerAndClient(testUser, testPass, testHandlerFunc(http.StatusOK, nil))
+		defer ts.Close()
+
+		RateLimit(0.1, 1)(c)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		if _, err := c.RegisterSeries(ctx, MakeSeries("series-code", "series-title")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := c.RegisterSeries(ctx, MakeSeries("series-code", "series-title")); ErrorCause(err) != context.DeadlineExceeded {
+			t.Fatalf("ErrorCause(err) = %v, want %v", ErrorCause(err), context.DeadlineExceeded)
+		}
+	})
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2017, 3, 27, 12, 0, 0, 0, time.UTC)
+
+	b := newTokenBucket(2, 2)
+	b.last = now
+	b.now = func() time.Time { return now }
+
+	for _, tt := range []struct {
+		advance time.Duration
+		want    time.Duration
+	}{
+		{0, 0},
+		{0, 0},
+		{0, 500 * time.Millisecond},
+		{time.Second, 0},
+		{10 * time.Second, 0},
+		{0, 0},
+		{0, 500 * time.Millisecond},
+	} {
+		now = now.Add(tt.advance)
+
+		if got := b.reserve(); got != tt.want {
+			t.Fatalf("b.reserve() = %v, want %v", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package titleservice
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy decides if, and after how long, a failed request should be retried
+//
+// Backoff is called after the given attempt (starting at 1) to the endpoint failed
+// with a retryable error. It returns the delay before the next attempt, or false
+// if no more attempts should be made.
+//
+// Requests rejected with status 400, 403 or 409 are never retried.
+type RetryPolicy interface {
+	Backoff(endpoint Endpoint, attempt int) (time.Duration, bool)
+}
+
+// ExponentialBackoff is a RetryPolicy doubling the delay between each attempt.
+// The actual delay is randomly picked between half and the full delay (jitter).
+type ExponentialBackoff struct {
+	MaxAttempts int           // total number of attempts, including the first one
+	BaseDelay   time.Duration // delay before the first retry
+	MaxDelay    time.Duration // upper bound of the delay (no bound if zero)
+}
+
+// DefaultRetryPolicy makes at most 4 attempts, waiting up to 0.5s, 1s and 2s between them
+var DefaultRetryPolicy = ExponentialBackoff{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// Backoff returns a jittered delay of at most BaseDelay * 2^(attempt-1)
+func (b ExponentialBackoff) Backoff(endpoint Endpoint, attempt int) (time.Duration, bool) {
+	if attempt >= b.MaxAttempts {
+		return 0, false
+	}
+
+	delay := b.BaseDelay
+
+	for i := 1; i < attempt && (b.MaxDelay <= 0 || delay < b.MaxDelay); i++ {
+		delay *= 2
+	}
+
+	if b.MaxDelay > 0 && delay > b.MaxDelay {
+		delay = b.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0, true
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)), true
+}
+
+// RetryPerEndpoint returns a RetryPolicy using the policy given for each endpoint,
+// falling back to the provided policy for endpoints not in the map
+func RetryPerEndpoint(policies map[Endpoint]RetryPolicy, fallback RetryPolicy) RetryPolicy {
+	return endpointRetryPolicy{policies: policies, fallback: fallback}
+}
+
+type endpointRetryPolicy struct {
+	policies map[Endpoint]RetryPolicy
+	fallback RetryPolicy
+}
+
+func (p endpointRetryPolicy) Backoff(endpoint Endpoint, attempt int) (time.Duration, bool) {
+	if policy, ok := p.policies[endpoint]; ok {
+		return policy.Backoff(endpoint, attempt)
+	}
+
+	if p.fallback == nil {
+		return 0, false
+	}
+
+	return p.fallback.Backoff(endpoint, attempt)
+}
+
+// retryable reports whether a request that failed with err is worth retrying
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+
+	var urlErr *url.Error
+
+	return errors.As(err, &urlErr)
+}
+
+// sleep waits for the delay to pass, returning false if ctx is done
+// before that or if the delay would exceed the ctx deadline
+func sleep(ctx context.Context, delay time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header,
+// which is either a number of seconds or a HTTP date
+func parseRetryAfter(v string) time.Duration {
+	v = strings.TrimSpace(v)
+
+	if v == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
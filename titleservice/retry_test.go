@@ -0,0 +1,156 @@
+package titleservice
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	policy := ExponentialBackoff{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	for _, tt := range []struct {
+		name     string
+		statuses []int
+		attempts int32
+		err      error
+	}{
+		{"ok", []int{http.StatusOK}, 1, nil},
+		{"server_errors", []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK}, 3, nil},
+		{"too_many_requests", []int{http.StatusTooManyRequests, http.StatusOK}, 2, nil},
+		{"max_attempts", []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK}, 3, ErrServiceUnavailable},
+		{"bad_request", []int{http.StatusBadRequest, http.StatusOK}, 1, ErrInvalidInputData},
+		{"forbidden", []int{http.StatusForbidden, http.StatusOK}, 1, ErrAuthenticationFailure},
+		{"conflict", []int{http.StatusConflict, http.StatusOK}, 1, ErrAlreadyRegistered},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+
+			ts, c := testServerAndClient(testUser, testPass, func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&attempts, 1)
+
+				testHandlerFunc(tt.statuses[n-1], nil)(w, r)
+			})
+			defer ts.Close()
+
+			Retry(policy)(c)
+
+			_, err := c.RegisterSeries(context.Background(), MakeSeries("series-code", "series-title"))
+			if got, want := ErrorCause(err), tt.err; got != want {
+				t.Fatalf("ErrorCause(err) = %v, want %v", got, want)
+			}
+
+			if got, want := atomic.LoadInt32(&attempts), tt.attempts; got != want {
+				t.Fatalf("attempts = %d, want %d", got, want)
+			}
+		})
+	}
+
+	t.Run("network_error", func(t *testing.T) {
+		ts, c := testServerAndClient(testUser, testPass, testHandlerFunc(http.StatusOK, nil))
+		ts.Close()
+
+		var attempts int32
+
+		Retry(retryPolicyFunc(func(endpoint Endpoint, attempt int) (time.Duration, bool) {
+			atomic.AddInt32(&attempts, 1)
+			return 0, attempt < 2
+		}))(c)
+
+		if _, err := c.RegisterSeries(context.Background(), MakeSeries("series-code", "series-title")); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		if got, want := atomic.LoadInt32(&attempts), int32(2); got != want {
+			t.Fatalf("attempts = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("retry_after_exceeds_deadline", func(t *testing.T) {
+		ts, c := testServerAndClient(testUser, testPass, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			testHandlerFunc(http.StatusServiceUnavailable, nil)(w, r)
+		})
+		defer ts.Close()
+
+		Retry(policy)(c)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		start := time.Now()
+
+		if _, err := c.RegisterSeries(ctx, MakeSeries("series-code", "series-title")); ErrorCause(err) != ErrServiceUnavailable {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("elapsed = %v, want the deadline to stop the retries", elapsed)
+		}
+	})
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	for _, tt := range []struct {
+		attempt int
+		max     time.Duration
+		ok      bool
+	}{
+		{1, 100 * time.Millisecond, true},
+		{2, 200 * time.Millisecond, true},
+		{3, 300 * time.Millisecond, true},
+		{4, 300 * time.Millisecond, true},
+		{5, 0, false},
+	} {
+		delay, ok := b.Backoff(RegisterEpisodeEndpoint, tt.attempt)
+
+		if ok != tt.ok {
+			t.Fatalf("b.Backoff(%d) ok = %v, want %v", tt.attempt, ok, tt.ok)
+		}
+
+		if delay < tt.max/2 || delay > tt.max {
+			t.Fatalf("b.Backoff(%d) = %v, want between %v and %v", tt.attempt, delay, tt.max/2, tt.max)
+		}
+	}
+}
+
+func TestRetryPerEndpoint(t *testing.T) {
+	p := RetryPerEndpoint(map[Endpoint]RetryPolicy{
+		RegisterClipEndpoint: ExponentialBackoff{MaxAttempts: 1},
+	}, ExponentialBackoff{MaxAttempts: 3})
+
+	if _, ok := p.Backoff(RegisterClipEndpoint, 1); ok {
+		t.Fatalf("p.Backoff(RegisterClipEndpoint, 1) ok = true, want false")
+	}
+
+	if _, ok := p.Backoff(RegisterEpisodeEndpoint, 1); !ok {
+		t.Fatalf("p.Backoff(RegisterEpisodeEndpoint, 1) ok = false, want true")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	for _, tt := range []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-1", 0},
+		{"invalid", 0},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0},
+	} {
+		if got := parseRetryAfter(tt.value); got != tt.want {
+			t.Fatalf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+type retryPolicyFunc func(endpoint Endpoint, attempt int) (time.Duration, bool)
+
+func (f retryPolicyFunc) Backoff(endpoint Endpoint, attempt int) (time.Duration, bool) {
+	return f(endpoint, attempt)
+}
@@ -62,19 +62,25 @@ func (s *Series) Params() (url.Values, error) {
 	return params, nil
 }
 
-// Validate all fields
+// Validate all fields, returning a *ValidationError listing every invalid field
 func (s *Series) Validate() error {
+	v := &ValidationError{Type: "Series"}
+
 	if s.SeriesCode == "" {
-		return newErrorWithMessage(ErrMissingParameter, "Series SeriesCode")
+		v.add("SeriesCode", FieldMissing)
 	}
 
 	if s.Title == "" {
-		return newErrorWithMessage(ErrMissingParameter, "Series Title")
+		v.add("Title", FieldMissing)
+	}
+
+	if s.SeasonNumber < 0 {
+		v.add("SeasonNumber", FieldBadFormat)
 	}
 
 	if strings.ContainsAny(s.Description, "<>") {
-		return newErrorWithMessage(ErrInvalidParameter, "Series Description")
+		v.add("Description", FieldBadFormat)
 	}
 
-	return nil
+	return v.err()
 }
@@ -34,11 +34,13 @@ func TestSeriesValidate(t *testing.T) {
 		s    *Series
 		want string
 	}{
-		{&Series{}, "Series SeriesCode: missing parameter"},
+		{&Series{}, "Series SeriesCode: missing parameter; Series Title: missing parameter"},
+		{&Series{Title: "T", Description: "<b>Foo</b>"}, "Series SeriesCode: missing parameter; Series Description: invalid parameter"},
 		{&Series{SeriesCode: "S"}, "Series Title: missing parameter"},
 		{&Series{SeriesCode: "S", Title: "T"}, "<nil>"},
 		{&Series{SeriesCode: "S", Title: "T", Description: "Foo"}, "<nil>"},
 		{&Series{SeriesCode: "S", Title: "T", Description: "<b>Foo</b>"}, "Series Description: invalid parameter"},
+		{&Series{SeriesCode: "S", Title: "T", SeasonNumber: -1}, "Series SeasonNumber: invalid parameter"},
 	} {
 		if got := fmt.Sprintf("%v", tt.s.Validate()); got != tt.want {
 			t.Fatalf("tt.s.Validate() = %q, want %q", got, tt.want)
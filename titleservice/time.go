@@ -2,6 +2,8 @@ package titleservice
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,12 +16,113 @@ func init() {
 	}
 }
 
+// MMSDate is a date in the MMS TitleService API, formatted as YYYYMMDD.
+// The zero value represents an unset date, and is formatted as an empty string.
+type MMSDate struct {
+	ymd int
+}
+
+// Date returns the MMSDate for a year, month and day, or the zero MMSDate if there is no such date
+func Date(year int, month time.Month, day int) MMSDate {
+	if year < 1 || year > 9999 || month < time.January || month > time.December || day < 1 {
+		return MMSDate{}
+	}
+
+	if t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC); t.Day() != day {
+		return MMSDate{}
+	}
+
+	return MMSDate{ymd: year*10000 + int(month)*100 + day}
+}
+
+// DateAtTime returns the date in Stockholm for the provided time.Time
+func DateAtTime(t time.Time) MMSDate {
+	return Date(t.In(Stockholm).Date())
+}
+
+// BroadcastDateAtTime returns the Live TV broadcast date for the provided time.Time
+func BroadcastDateAtTime(t time.Time) MMSDate {
+	return DateAtTime(t.Add(-2 * time.Hour))
+}
+
+// ParseDate parses a date in the format YYYYMMDD
+func ParseDate(s string) (MMSDate, error) {
+	if len(s) != 8 {
+		return MMSDate{}, newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("date %q is not in the format YYYYMMDD", s))
+	}
+
+	t, err := time.Parse("20060102", s)
+	if err != nil {
+		return MMSDate{}, newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("date %q does not exist", s))
+	}
+
+	return Date(t.Date()), nil
+}
+
+// IsZero returns true if the date is unset
+func (d MMSDate) IsZero() bool {
+	return d.ymd == 0
+}
+
+// Before returns true if d is before u
+func (d MMSDate) Before(u MMSDate) bool {
+	return d.ymd < u.ymd
+}
+
+// Time returns midnight in Stockholm at the date
+func (d MMSDate) Time() time.Time {
+	if d.IsZero() {
+		return time.Time{}
+	}
+
+	return time.Date(d.ymd/10000, time.Month(d.ymd/100%100), d.ymd%100, 0, 0, 0, 0, Stockholm)
+}
+
+// String returns the date in the format YYYYMMDD, or an empty string if the date is unset
+func (d MMSDate) String() string {
+	if d.IsZero() {
+		return ""
+	}
+
+	return fmt.Sprintf("%08d", d.ymd)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d MMSDate) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *MMSDate) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = MMSDate{}
+		return nil
+	}
+
+	parsed, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// MMSTime is a time in the MMS Live TV broadcast time format, HHMM between 0200 and 2559,
+// where the times after midnight belong to the previous broadcast day.
+// The zero value represents an unset time, and is formatted as an empty string.
+type MMSTime struct {
+	hhmm int
+}
+
 // Time formats an hour and minute into the format HHMM
 func Time(hour, minute int) string {
 	return fmt.Sprintf("%02d%02d", hour, minute)
 }
 
-// BroadcastTime formats an hour and minute into the MMS Live TV broadcast time format
+// BroadcastTime returns the MMS Live TV broadcast time for an hour and minute,
+// or the zero MMSTime if the hour or minute is out of range
 //
 // 0200-2559 (The leading zero is optional)
 //
@@ -30,29 +133,82 @@ func Time(hour, minute int) string {
 //   BroadcastTime(1,45)  = 2545 (day 1)
 //   BroadcastTime(2,0)   = 0200 (day 2)
 //
-func BroadcastTime(hour, minute int) string {
+func BroadcastTime(hour, minute int) MMSTime {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return MMSTime{}
+	}
+
 	if hour < 2 {
-		return Time(hour+24, minute)
+		hour += 24
+	}
+
+	return MMSTime{hhmm: hour*100 + minute}
+}
+
+// BroadcastTimeAtTime returns the MMS Live TV broadcast time in Stockholm for the provided time.Time
+func BroadcastTimeAtTime(t time.Time) MMSTime {
+	t = t.In(Stockholm)
+
+	return BroadcastTime(t.Hour(), t.Minute())
+}
+
+// ParseMMSTime parses a time in the MMS Live TV broadcast time format (0200-2559, the leading zero is optional)
+func ParseMMSTime(s string) (MMSTime, error) {
+	if len(s) != 3 && len(s) != 4 || strings.Trim(s, "0123456789") != "" {
+		return MMSTime{}, newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("time %q is not in the format HHMM", s))
 	}
 
-	return Time(hour, minute)
+	n, _ := strconv.Atoi(s)
+
+	if hour, minute := n/100, n%100; hour < 2 || hour > 25 || minute > 59 {
+		return MMSTime{}, newErrorWithMessage(ErrInvalidParameter, fmt.Sprintf("time %q is not between 0200 and 2559", s))
+	}
+
+	return MMSTime{hhmm: n}, nil
 }
 
-// Date formats a year, month, day into the format YYYYMMDD
-func Date(year int, month time.Month, day int) string {
-	if year > 0 && month > 0 && month < 13 && day > 0 && day < 32 {
-		return fmt.Sprintf("%04d%02d%02d", year, month, day)
+// IsZero returns true if the time is unset
+func (t MMSTime) IsZero() bool {
+	return t.hhmm == 0
+}
+
+// Hour returns the hour of the time, between 2 and 25
+func (t MMSTime) Hour() int {
+	return t.hhmm / 100
+}
+
+// Minute returns the minute of the time
+func (t MMSTime) Minute() int {
+	return t.hhmm % 100
+}
+
+// String returns the time in the format HHMM, or an empty string if the time is unset
+func (t MMSTime) String() string {
+	if t.IsZero() {
+		return ""
 	}
 
-	return ""
+	return fmt.Sprintf("%04d", t.hhmm)
 }
 
-// DateAtTime returns the date string for the provided time.Time
-func DateAtTime(t time.Time) string {
-	return Date(t.In(Stockholm).Date())
+// MarshalText implements encoding.TextMarshaler
+func (t MMSTime) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
 }
 
-// BroadcastDateAtTime returns the Live TV broadcast date string for the provided time.Time
-func BroadcastDateAtTime(t time.Time) string {
-	return DateAtTime(t.Add(-2 * time.Hour))
+// UnmarshalText implements encoding.TextUnmarshaler
+func (t *MMSTime) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*t = MMSTime{}
+		return nil
+	}
+
+	parsed, err := ParseMMSTime(string(text))
+	if err != nil {
+		return err
+	}
+
+	*t = parsed
+
+	return nil
 }
@@ -1,6 +1,8 @@
 package titleservice
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 )
@@ -32,8 +34,10 @@ func TestBroadcastTime(t *testing.T) {
 		{0, 15, "2415"},
 		{1, 45, "2545"},
 		{2, 0, "0200"},
+		{24, 0, ""},
+		{12, 60, ""},
 	} {
-		if got := BroadcastTime(tt.hour, tt.minute); got != tt.want {
+		if got := BroadcastTime(tt.hour, tt.minute).String(); got != tt.want {
 			t.Fatalf("BroadcastTime(%d, %d) = %q, want %q", tt.hour, tt.minute, got, tt.want)
 		}
 	}
@@ -53,8 +57,11 @@ func TestDate(t *testing.T) {
 		{2007, 6, 15, "20070615"},
 		{2017, 8, 15, "20170815"},
 		{2017, 3, 27, "20170327"},
+		{2017, 2, 29, ""},
+		{2016, 2, 29, "20160229"},
+		{2017, 13, 1, ""},
 	} {
-		if got := Date(tt.year, tt.month, tt.day); got != tt.want {
+		if got := Date(tt.year, tt.month, tt.day).String(); got != tt.want {
 			t.Fatalf("Date(%d, %d, %d) = %q, want %q", tt.year, tt.month, tt.day, got, tt.want)
 		}
 	}
@@ -74,7 +81,7 @@ func TestDateAtTime(t *testing.T) {
 		{time.Date(2017, time.October, 29, 2, 5, 0, 0, Stockholm), "20171029"},
 		{time.Date(2017, time.October, 29, 3, 0, 0, 0, Stockholm), "20171029"},
 	} {
-		if got := DateAtTime(tt.time); got != tt.want {
+		if got := DateAtTime(tt.time).String(); got != tt.want {
 			t.Fatalf("DateAtTime(<%s>) = %q, want %q", tt.time, got, tt.want)
 		}
 	}
@@ -94,8 +101,103 @@ func TestBroadcastDateAtTime(t *testing.T) {
 		{time.Date(2017, time.October, 29, 2, 5, 0, 0, Stockholm), "20171029"},
 		{time.Date(2017, time.October, 29, 3, 0, 0, 0, Stockholm), "20171029"},
 	} {
-		if got := BroadcastDateAtTime(tt.time); got != tt.want {
+		if got := BroadcastDateAtTime(tt.time).String(); got != tt.want {
 			t.Fatalf("BroadcastDateAtTime(<%s>) = %q, want %q", tt.time, got, tt.want)
 		}
 	}
 }
+
+func TestBroadcastTimeAtTime(t *testing.T) {
+	for _, tt := range []struct {
+		time time.Time
+		want string
+	}{
+		{time.Date(2017, time.March, 26, 1, 0, 0, 0, Stockholm), "2500"},
+		{time.Date(2017, time.March, 26, 12, 30, 0, 0, time.UTC), "1430"},
+		{time.Date(2017, time.October, 29, 2, 5, 0, 0, Stockholm), "0205"},
+	} {
+		if got := BroadcastTimeAtTime(tt.time).String(); got != tt.want {
+			t.Fatalf("BroadcastTimeAtTime(<%s>) = %q, want %q", tt.time, got, tt.want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want string
+		err  error
+	}{
+		{"20170327", "20170327", nil},
+		{"", "", ErrInvalidParameter},
+		{"2017-03-27", "", ErrInvalidParameter},
+		{"20170230", "", ErrInvalidParameter},
+		{"2017032a", "", ErrInvalidParameter},
+	} {
+		d, err := ParseDate(tt.s)
+
+		if got := ErrorCause(err); got != tt.err {
+			t.Fatalf("ParseDate(%q) err = %v, want %v", tt.s, err, tt.err)
+		}
+
+		if got := d.String(); got != tt.want {
+			t.Fatalf("ParseDate(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParseMMSTime(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want string
+		err  error
+	}{
+		{"0200", "0200", nil},
+		{"200", "0200", nil},
+		{"2559", "2559", nil},
+		{"0145", "", ErrInvalidParameter},
+		{"2600", "", ErrInvalidParameter},
+		{"1260", "", ErrInvalidParameter},
+		{"+945", "", ErrInvalidParameter},
+		{"12:30", "", ErrInvalidParameter},
+	} {
+		mt, err := ParseMMSTime(tt.s)
+
+		if got := ErrorCause(err); got != tt.err {
+			t.Fatalf("ParseMMSTime(%q) err = %v, want %v", tt.s, err, tt.err)
+		}
+
+		if got := mt.String(); got != tt.want {
+			t.Fatalf("ParseMMSTime(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestMMSDateAndTimeJSON(t *testing.T) {
+	in := Episode{PublishedAt: Date(2017, 3, 27), LiveTime: BroadcastTime(0, 15)}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{`"published_at":"20170327"`, `"live_time":"2415"`, `"available_until":""`, `"live_tv_day":""`} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("json.Marshal(in) = %s, want it to contain %s", b, want)
+		}
+	}
+
+	var out Episode
+
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out.PublishedAt != in.PublishedAt || out.LiveTime != in.LiveTime || !out.AvailableUntil.IsZero() {
+		t.Fatalf("json.Unmarshal(%s) = %+v, want %+v", b, out, in)
+	}
+
+	if err := json.Unmarshal([]byte(`{"published_at":"20170230"}`), &out); ErrorCause(err) != ErrInvalidParameter {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,471 @@
+/*
+
+Package titleservicetest provides an in-process fake of the MMS TitleService API, for use in tests
+
+	ts := titleservicetest.NewServer("user", "pass")
+	defer ts.Close()
+
+	c := ts.Client()
+
+	resp, err := c.RegisterSeries(ctx, titleservice.MakeSeries("SC", "Title"))
+
+	registered := ts.Series()
+
+*/
+package titleservicetest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TV4/mms/titleservice"
+)
+
+// Server is a fake MMS TitleService API
+//
+// It checks the credentials (403), validates the parameters (400), rejects
+// already registered SeriesCodes and TitleCodes (409) and updates of titles
+// that are not registered (404). Simulated requests are validated, but never persisted.
+type Server struct {
+	*httptest.Server
+
+	username string
+	password string
+
+	mu       sync.Mutex
+	series   map[string]titleservice.Series
+	episodes map[string]titleservice.Episode
+	clips    map[string]titleservice.Clip
+	requests []Request
+	failures map[titleservice.Endpoint][]int
+	latency  time.Duration
+}
+
+// Request is a request received by the Server
+type Request struct {
+	Endpoint titleservice.Endpoint
+	Params   url.Values // the form parameters, with the password removed
+	Simulate bool
+	Status   int // the status code of the response
+}
+
+// NewServer starts a Server accepting the provided credentials.
+// The caller should call Close when finished, to shut it down.
+func NewServer(username, password string) *Server {
+	s := &Server{
+		username: username,
+		password: password,
+	}
+
+	s.Reset()
+
+	s.Server = httptest.NewServer(s)
+
+	return s
+}
+
+// Client returns a *titleservice.Client configured to send requests to the Server
+// using its credentials. Any options are applied after the default ones.
+func (s *Server) Client(options ...func(*titleservice.Client)) *titleservice.Client {
+	options = append([]func(*titleservice.Client){
+		titleservice.BaseURL(s.URL),
+		titleservice.HTTPClient(s.Server.Client()),
+	}, options...)
+
+	return titleservice.NewClient(s.username, s.password, options...)
+}
+
+// Reset removes everything registered, received and injected
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.series = map[string]titleservice.Series{}
+	s.episodes = map[string]titleservice.Episode{}
+	s.clips = map[string]titleservice.Clip{}
+	s.requests = nil
+	s.failures = map[titleservice.Endpoint][]int{}
+	s.latency = 0
+}
+
+// Series returns the registered series, ordered by SeriesCode
+func (s *Server) Series() []titleservice.Series {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series := make([]titleservice.Series, 0, len(s.series))
+
+	for _, v := range s.series {
+		series = append(series, v)
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].SeriesCode < series[j].SeriesCode
+	})
+
+	return series
+}
+
+// Episodes returns the registered episodes, ordered by TitleCode
+func (s *Server) Episodes() []titleservice.Episode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	episodes := make([]titleservice.Episode, 0, len(s.episodes))
+
+	for _, v := range s.episodes {
+		episodes = append(episodes, v)
+	}
+
+	sort.Slice(episodes, func(i, j int) bool {
+		return episodes[i].TitleCode < episodes[j].TitleCode
+	})
+
+	return episodes
+}
+
+// Clips returns the registered clips, ordered by TitleCode
+func (s *Server) Clips() []titleservice.Clip {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clips := make([]titleservice.Clip, 0, len(s.clips))
+
+	for _, v := range s.clips {
+		clips = append(clips, v)
+	}
+
+	sort.Slice(clips, func(i, j int) bool {
+		return clips[i].TitleCode < clips[j].TitleCode
+	})
+
+	return clips
+}
+
+// Requests returns every request received by the Server, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// Fail makes the next requests to the endpoint fail with the provided status codes, in order
+func (s *Server) Fail(endpoint titleservice.Endpoint, statusCodes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[endpoint] = append(s.failures[endpoint], statusCodes...)
+}
+
+// Latency delays every response by d
+func (s *Server) Latency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = d
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeResponse(w, http.StatusMethodNotAllowed)
+		return
+	}
+
+	endpoint := titleservice.Endpoint(strings.TrimPrefix(r.URL.Path, "/"))
+
+	if err := r.ParseForm(); err != nil {
+		writeResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	latency := s.latency
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(latency):
+		}
+	}
+
+	status, errs := s.handle(endpoint, r.PostForm)
+
+	writeResponse(w, status, errs...)
+}
+
+func (s *Server) handle(endpoint titleservice.Endpoint, params url.Values) (int, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, simulate := params["simulate"]
+
+	recorded := url.Values{}
+
+	for key, values := range params {
+		if key != "pass" {
+			recorded[key] = append([]string(nil), values...)
+		}
+	}
+
+	req := Request{Endpoint: endpoint, Params: recorded, Simulate: simulate}
+
+	status, errs := s.register(endpoint, params, simulate)
+
+	req.Status = status
+
+	s.requests = append(s.requests, req)
+
+	return status, errs
+}
+
+func (s *Server) register(endpoint titleservice.Endpoint, params url.Values, simulate bool) (int, []string) {
+	if failures := s.failures[endpoint]; len(failures) > 0 {
+		s.failures[endpoint] = failures[1:]
+
+		return failures[0], nil
+	}
+
+	if params.Get("user") != s.username || params.Get("pass") != s.password {
+		return http.StatusForbidden, []string{"invalid username or password"}
+	}
+
+	switch endpoint {
+	case titleservice.RegisterSeriesEndpoint:
+		series, errs := decodeSeries(params)
+		if len(errs) > 0 {
+			return http.StatusBadRequest, errs
+		}
+
+		if _, ok := s.series[series.SeriesCode]; ok {
+			return http.StatusConflict, []string{"SeriesCode " + series.SeriesCode + " is already registered"}
+		}
+
+		if !simulate {
+			s.series[series.SeriesCode] = series
+		}
+	case titleservice.RegisterEpisodeEndpoint:
+		episode, errs := decodeEpisode(params)
+		if len(errs) > 0 {
+			return http.StatusBadRequest, errs
+		}
+
+		if s.titleCodeRegistered(episode.TitleCode) {
+			return http.StatusConflict, []string{"TitleCode " + episode.TitleCode + " is already registered"}
+		}
+
+		if !simulate {
+			s.episodes[episode.TitleCode] = episode
+		}
+	case titleservice.RegisterClipEndpoint:
+		clip, errs := decodeClip(params)
+		if len(errs) > 0 {
+			return http.StatusBadRequest, errs
+		}
+
+		if s.titleCodeRegistered(clip.TitleCode) {
+			return http.StatusConflict, []string{"TitleCode " + clip.TitleCode + " is already registered"}
+		}
+
+		if !simulate {
+			s.clips[clip.TitleCode] = clip
+		}
+	case titleservice.UpdateSeriesEndpoint:
+		series, errs := decodeSeries(params)
+		if len(errs) > 0 {
+			return http.StatusBadRequest, errs
+		}
+
+		if _, ok := s.series[series.SeriesCode]; !ok {
+			return http.StatusNotFound, []string{"SeriesCode " + series.SeriesCode + " is not registered"}
+		}
+
+		if !simulate {
+			s.series[series.SeriesCode] = series
+		}
+	case titleservice.UpdateEpisodeEndpoint:
+		episode, errs := decodeEpisode(params)
+		if len(errs) > 0 {
+			return http.StatusBadRequest, errs
+		}
+
+		if _, ok := s.episodes[episode.TitleCode]; !ok {
+			return http.StatusNotFound, []string{"Episode " + episode.TitleCode + " is not registered"}
+		}
+
+		if !simulate {
+			s.episodes[episode.TitleCode] = episode
+		}
+	case titleservice.UpdateClipEndpoint:
+		clip, errs := decodeClip(params)
+		if len(errs) > 0 {
+			return http.StatusBadRequest, errs
+		}
+
+		if _, ok := s.clips[clip.TitleCode]; !ok {
+			return http.StatusNotFound, []string{"Clip " + clip.TitleCode + " is not registered"}
+		}
+
+		if !simulate {
+			s.clips[clip.TitleCode] = clip
+		}
+	default:
+		return http.StatusNotFound, []string{"unknown endpoint " + string(endpoint)}
+	}
+
+	return http.StatusOK, nil
+}
+
+func (s *Server) titleCodeRegistered(titleCode string) bool {
+	_, episode := s.episodes[titleCode]
+	_, clip := s.clips[titleCode]
+
+	return episode || clip
+}
+
+func writeResponse(w http.ResponseWriter, status int, errs ...string) {
+	if errs == nil {
+		errs = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(titleservice.Response{
+		StatusCode:        status,
+		StatusDescription: http.StatusText(status),
+		Errors:            errs,
+	})
+}
+
+// form decodes the parameters of a request, collecting the errors of parameters
+// that can't be parsed. Validation of the decoded values is left to Validate.
+type form struct {
+	params url.Values
+	errs   []string
+}
+
+func (f *form) string(key string) string {
+	return f.params.Get(key)
+}
+
+func (f *form) int(key string) int {
+	v := f.params.Get(key)
+
+	if v == "" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		f.errs = append(f.errs, key+": invalid parameter (not an integer)")
+	}
+
+	return n
+}
+
+func (f *form) date(key string) titleservice.MMSDate {
+	var d titleservice.MMSDate
+
+	if err := d.UnmarshalText([]byte(f.params.Get(key))); err != nil {
+		f.errs = append(f.errs, key+": "+titleservice.ErrorCause(err).Error()+" (YYYYMMDD)")
+	}
+
+	return d
+}
+
+func (f *form) time(key string) titleservice.MMSTime {
+	var t titleservice.MMSTime
+
+	if err := t.UnmarshalText([]byte(f.params.Get(key))); err != nil {
+		f.errs = append(f.errs, key+": "+titleservice.ErrorCause(err).Error()+" (HHMM between 0200 and 2559)")
+	}
+
+	return t
+}
+
+// validate returns the parse errors followed by the errors from Validate
+func (f *form) validate(r interface{ Validate() error }) []string {
+	errs := f.errs
+
+	var v *titleservice.ValidationError
+
+	if err := r.Validate(); errors.As(err, &v) {
+		for _, field := range v.Fields {
+			errs = append(errs, field.Error())
+		}
+	}
+
+	return errs
+}
+
+func decodeSeries(params url.Values) (titleservice.Series, []string) {
+	f := &form{params: params}
+
+	s := titleservice.Series{
+		SeriesCode:   f.string("SeriesCode"),
+		Title:        f.string("Title"),
+		SeasonNumber: f.int("SeasonNumber"),
+		Description:  f.string("Description"),
+		GenreText:    f.string("GenreText"),
+	}
+
+	return s, f.validate(&s)
+}
+
+func decodeEpisode(params url.Values) (titleservice.Episode, []string) {
+	f := &form{params: params}
+
+	e := titleservice.Episode{
+		TitleCode:       f.string("TitleCode"),
+		SeriesCode:      f.string("SeriesCode"),
+		Title:           f.string("Title"),
+		Length:          f.int("Length"),
+		PublishedAt:     f.date("PublishedAt"),
+		AvailableUntil:  f.date("AvailableUntil"),
+		CategoryID:      titleservice.CategoryID(f.int("CategoryID")),
+		EpisodeNumber:   f.int("EpisodeNumber"),
+		Description:     f.string("Description"),
+		LinkedTitleCode: f.string("LinkedTitleCode"),
+		LiveTitle:       f.string("LiveTitle"),
+		LiveTvDay:       f.date("LiveTvDay"),
+		LiveTime:        f.time("LiveTime"),
+		LiveChannelID:   titleservice.LiveChannelID(f.int("LiveChannelID")),
+		PlayURL:         f.string("PlayUrl"),
+		TargetGroupCode: titleservice.TargetGroup(f.string("TargetGroupCode")),
+		TerritoryCode:   titleservice.Territory(f.string("TerritoryCode")),
+		SuggestedGenre1: f.string("SuggestedGenre1"),
+		SuggestedGenre2: f.string("SuggestedGenre2"),
+		SuggestedGenre3: f.string("SuggestedGenre3"),
+	}
+
+	return e, f.validate(&e)
+}
+
+func decodeClip(params url.Values) (titleservice.Clip, []string) {
+	f := &form{params: params}
+
+	c := titleservice.Clip{
+		TitleCode:      f.string("TitleCode"),
+		Title:          f.string("Title"),
+		Length:         f.int("Length"),
+		PublishedAt:    f.date("PublishedAt"),
+		AvailableUntil: f.date("AvailableUntil"),
+		Description:    f.string("Description"),
+		PlayURL:        f.string("PlayUrl"),
+	}
+
+	return c, f.validate(&c)
+}
@@ -0,0 +1,211 @@
+package titleservicetest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TV4/mms/titleservice"
+)
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("register", func(t *testing.T) {
+		ts := NewServer("user", "pass")
+		defer ts.Close()
+
+		c := ts.Client()
+
+		if _, err := c.RegisterSeries(ctx, titleservice.MakeSeries("SC", "Series", titleservice.WithSeasonNumber(2))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		episode := titleservice.MakeEpisode("TC1", "SC", "Episode", 60, titleservice.Date(2017, 3, 27), titleservice.TvProgram,
+			titleservice.WithLive("Live", titleservice.Date(2017, 3, 26), titleservice.BroadcastTime(1, 30), titleservice.TV4),
+			titleservice.WithTerritory(titleservice.Swedish),
+		)
+
+		if _, err := c.RegisterEpisode(ctx, episode); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		clip := titleservice.MakeClip("TC2", "Clip", 30, titleservice.Date(2017, 3, 27))
+
+		if _, err := c.RegisterClip(ctx, clip); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := ts.Series()[0].SeasonNumber, 2; got != want {
+			t.Fatalf("ts.Series()[0].SeasonNumber = %d, want %d", got, want)
+		}
+
+		if got := ts.Episodes(); len(got) != 1 || got[0] != episode {
+			t.Fatalf("ts.Episodes() = %+v, want [%+v]", got, episode)
+		}
+
+		if got := ts.Clips(); len(got) != 1 || got[0] != clip {
+			t.Fatalf("ts.Clips() = %+v, want [%+v]", got, clip)
+		}
+
+		for _, r := range ts.Requests() {
+			if _, ok := r.Params["pass"]; ok {
+				t.Fatalf("r.Params contains the password")
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		ts := NewServer("user", "pass")
+		defer ts.Close()
+
+		clip := titleservice.MakeClip("TC", "Clip", 30, titleservice.Date(2017, 3, 27))
+
+		if _, err := ts.Client().RegisterClip(ctx, clip); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		episode := titleservice.MakeEpisode("TC", "SC", "Episode", 60, titleservice.Date(2017, 3, 27), titleservice.Webisode)
+
+		_, err := ts.Client().RegisterEpisode(ctx, episode)
+		if !errors.Is(err, titleservice.ErrAlreadyRegistered) {
+			t.Fatalf("err = %v, want %v", err, titleservice.ErrAlreadyRegistered)
+		}
+
+		_, err = titleservice.NewClient("user", "wrong", titleservice.BaseURL(ts.URL)).RegisterClip(ctx, clip)
+		if !errors.Is(err, titleservice.ErrAuthenticationFailure) {
+			t.Fatalf("err = %v, want %v", err, titleservice.ErrAuthenticationFailure)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		ts := NewServer("user", "pass")
+		defer ts.Close()
+
+		c := ts.Client()
+
+		clip := titleservice.MakeClip("TC", "Clip", 30, titleservice.Date(2017, 3, 27))
+
+		_, err := c.UpdateClip(ctx, clip)
+		if !errors.Is(err, titleservice.ErrNotRegistered) {
+			t.Fatalf("err = %v, want %v", err, titleservice.ErrNotRegistered)
+		}
+
+		if _, err := c.RegisterClip(ctx, clip); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = c.UpdateEpisode(ctx, titleservice.MakeEpisode("TC", "SC", "Episode", 60, titleservice.Date(2017, 3, 27), titleservice.Webisode))
+		if !errors.Is(err, titleservice.ErrNotRegistered) {
+			t.Fatalf("err = %v, want %v", err, titleservice.ErrNotRegistered)
+		}
+
+		clip.Length = 45
+
+		if _, err := c.UpdateClip(ctx, clip); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := ts.Clips(); len(got) != 1 || got[0] != clip {
+			t.Fatalf("ts.Clips() = %+v, want [%+v]", got, clip)
+		}
+
+		if _, err := c.RegisterSeries(ctx, titleservice.MakeSeries("SC", "Series")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := c.UpdateSeries(ctx, titleservice.MakeSeries("SC", "Series", titleservice.WithSeasonNumber(3))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := ts.Series()[0].SeasonNumber, 3; got != want {
+			t.Fatalf("ts.Series()[0].SeasonNumber = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("invalid_parameters", func(t *testing.T) {
+		ts := NewServer("user", "pass")
+		defer ts.Close()
+
+		resp, err := http.PostForm(ts.URL+"/RegisterEpisode", url.Values{
+			"user":        {"user"},
+			"pass":        {"pass"},
+			"TitleCode":   {"TC"},
+			"Length":      {"long"},
+			"PublishedAt": {"20170230"},
+			"CategoryID":  {"4"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if got, want := resp.StatusCode, http.StatusBadRequest; got != want {
+			t.Fatalf("resp.StatusCode = %d, want %d", got, want)
+		}
+
+		_, err = ts.Client().RegisterSeries(ctx, titleservice.Series{SeriesCode: "SC"})
+		if !errors.Is(err, titleservice.ErrMissingParameter) {
+			t.Fatalf("err = %v, want %v", err, titleservice.ErrMissingParameter)
+		}
+	})
+
+	t.Run("simulate", func(t *testing.T) {
+		ts := NewServer("user", "pass")
+		defer ts.Close()
+
+		if _, err := ts.Client(titleservice.Simulate(true)).RegisterSeries(ctx, titleservice.MakeSeries("SC", "Series")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := ts.Series(); len(got) != 0 {
+			t.Fatalf("ts.Series() = %+v, want none", got)
+		}
+
+		if got := ts.Requests(); len(got) != 1 || !got[0].Simulate {
+			t.Fatalf("ts.Requests() = %+v, want one simulated request", got)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		ts := NewServer("user", "pass")
+		defer ts.Close()
+
+		ts.Fail(titleservice.RegisterSeriesEndpoint, http.StatusServiceUnavailable)
+
+		c := ts.Client(titleservice.Retry(titleservice.ExponentialBackoff{MaxAttempts: 2}))
+
+		if _, err := c.RegisterSeries(ctx, titleservice.MakeSeries("SC", "Series")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		requests := ts.Requests()
+
+		if got, want := len(requests), 2; got != want {
+			t.Fatalf("len(requests) = %d, want %d", got, want)
+		}
+
+		if got, want := requests[0].Status, http.StatusServiceUnavailable; got != want {
+			t.Fatalf("requests[0].Status = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		ts := NewServer("user", "pass")
+		defer ts.Close()
+
+		ts.Latency(200 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		_, err := ts.Client().RegisterSeries(ctx, titleservice.MakeSeries("SC", "Series"))
+		if err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+			t.Fatalf("err = %v, want deadline exceeded", err)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package titleservice
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer starts a span for every call sent by a Client, such as the
+// OpenTelemetry adapter in github.com/TV4/mms/titleservice/otel
+type Tracer interface {
+	// Start a span for the call, returning a context containing it.
+	// It is called before the call is passed to the middleware.
+	Start(ctx context.Context, call Call) (context.Context, Span)
+
+	// Inject the span in ctx into the header of an outbound HTTP request
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span of a call, ended once the call has returned
+type Span interface {
+	// SetStatusCode is called with the HTTP status code of every response received
+	SetStatusCode(code int)
+
+	// End the span, recording err if the call failed
+	End(err error)
+}
+
+// Trace configures the *client to start a span for every call using t
+func Trace(t Tracer) func(*Client) {
+	return func(c *Client) {
+		c.tracer = t
+	}
+}
+
+type spanKey struct{}
+
+// spanFromContext returns the span started by the Tracer of the client, if any
+func spanFromContext(ctx context.Context) Span {
+	span, _ := ctx.Value(spanKey{}).(Span)
+
+	return span
+}
+
+// startSpan starts a span for the call if the client has a Tracer, returning
+// the context to use for the call and a func to end the span
+func (c *Client) startSpan(ctx context.Context, call Call) (context.Context, func(error)) {
+	if c.tracer == nil {
+		return ctx, func(error) {}
+	}
+
+	ctx, span := c.tracer.Start(ctx, call)
+
+	return context.WithValue(ctx, spanKey{}, span), span.End
+}
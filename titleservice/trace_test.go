@@ -0,0 +1,110 @@
+package titleservice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type testTracer struct {
+	calls []Call
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, call Call) (context.Context, Span) {
+	span := &testSpan{id: "span-" + string(call.Endpoint)}
+
+	t.calls = append(t.calls, call)
+	t.spans = append(t.spans, span)
+
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func (t *testTracer) Inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(testSpanKey{}).(*testSpan); ok {
+		header.Set("Traceparent", span.id)
+	}
+}
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	id          string
+	statusCodes []int
+	ended       bool
+	err         error
+}
+
+func (s *testSpan) SetStatusCode(code int) {
+	s.statusCodes = append(s.statusCodes, code)
+}
+
+func (s *testSpan) End(err error) {
+	s.ended, s.err = true, err
+}
+
+func TestTrace(t *testing.T) {
+	var (
+		statuses = []int{http.StatusServiceUnavailable, http.StatusConflict}
+		headers  []string
+	)
+
+	ts, c := testServerAndClient(testUser, testPass, func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get("Traceparent"))
+
+		status := statuses[0]
+		statuses = statuses[1:]
+
+		testHandlerFunc(status, nil)(w, r)
+	})
+	defer ts.Close()
+
+	tracer := &testTracer{}
+
+	Trace(tracer)(c)
+	Simulate(true)(c)
+	Retry(ExponentialBackoff{MaxAttempts: 2, BaseDelay: time.Millisecond})(c)
+
+	episode := MakeEpisode("TC1", "SC", "Episode", 60, Date(2017, 3, 27), Webisode)
+
+	_, err := c.RegisterEpisode(context.Background(), episode)
+	if !errors.Is(err, ErrAlreadyRegistered) {
+		t.Fatalf("err = %v, want %v", err, ErrAlreadyRegistered)
+	}
+
+	if got, want := len(tracer.spans), 1; got != want {
+		t.Fatalf("len(tracer.spans) = %d, want %d", got, want)
+	}
+
+	call, span := tracer.calls[0], tracer.spans[0]
+
+	if call.Endpoint != RegisterEpisodeEndpoint || !call.Simulate || call.Request.(*Episode).TitleCode != "TC1" {
+		t.Fatalf("call = %+v, want a simulated RegisterEpisode call of TC1", call)
+	}
+
+	if got, want := span.statusCodes, []int{503, 409}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("span.statusCodes = %v, want %v", got, want)
+	}
+
+	if !span.ended || span.err != err {
+		t.Fatalf("span ended = %v with %v, want ended with %v", span.ended, span.err, err)
+	}
+
+	for i, h := range headers {
+		if got, want := h, "span-RegisterEpisode"; got != want {
+			t.Fatalf("headers[%d] = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestTraceDisabled(t *testing.T) {
+	ctx, end := testClient().startSpan(context.Background(), Call{})
+
+	end(nil)
+
+	if spanFromContext(ctx) != nil {
+		t.Fatalf("expected no span without a Tracer")
+	}
+}
@@ -0,0 +1,38 @@
+package titleservice
+
+import "context"
+
+// update is a Request sent to the update endpoint of the title type
+type update struct {
+	Request
+
+	endpoint Endpoint
+}
+
+// Endpoint returns the update endpoint
+func (u update) Endpoint() Endpoint {
+	return u.endpoint
+}
+
+// UpdateSeries replaces the content of an already registered Series, identified by its SeriesCode
+func (c *Client) UpdateSeries(ctx context.Context, series Series) (*Response, error) {
+	return c.register(ctx, update{&series, UpdateSeriesEndpoint})
+}
+
+// UpdateEpisode replaces the content of an already registered Episode, identified by its TitleCode
+func (c *Client) UpdateEpisode(ctx context.Context, episode Episode) (*Response, error) {
+	return c.register(ctx, update{&episode, UpdateEpisodeEndpoint})
+}
+
+// UpdateClip replaces the content of an already registered Clip, identified by its TitleCode
+func (c *Client) UpdateClip(ctx context.Context, clip Clip) (*Response, error) {
+	return c.register(ctx, update{&clip, UpdateClipEndpoint})
+}
+
+// Upsert configures the Ensure methods of the *client to update titles already
+// registered with different content, instead of reporting a Conflict
+func Upsert(b bool) func(*Client) {
+	return func(c *Client) {
+		c.upsert = b
+	}
+}
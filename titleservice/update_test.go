@@ -0,0 +1,83 @@
+package titleservice
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClientUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	var paths []string
+
+	ts, c := testServerAndClient(testUser, testPass, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		testHandlerFunc(http.StatusOK, nil)(w, r)
+	})
+	defer ts.Close()
+
+	episode := MakeEpisode("TC1", "SC", "Episode", 60, Date(2017, 3, 27), TvSegment, WithLinkedTitleCode("TC0"),
+		WithLive("Live", Date(2017, 3, 26), BroadcastTime(20, 0), TV4),
+	)
+
+	for _, update := range []func() (*Response, error){
+		func() (*Response, error) { return c.UpdateSeries(ctx, MakeSeries("SC", "Series")) },
+		func() (*Response, error) { return c.UpdateEpisode(ctx, episode) },
+		func() (*Response, error) { return c.UpdateClip(ctx, MakeClip("TC2", "Clip", 30, Date(2017, 3, 27))) },
+	} {
+		if _, err := update(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for i, want := range []Endpoint{UpdateSeriesEndpoint, UpdateEpisodeEndpoint, UpdateClipEndpoint} {
+		if got := paths[i]; got != "/"+string(want) {
+			t.Fatalf("paths[%d] = %q, want %q", i, got, "/"+string(want))
+		}
+	}
+
+	if _, err := c.UpdateSeries(ctx, Series{}); ErrorCause(err) != ErrMissingParameter {
+		t.Fatalf("err = %v, want %v", err, ErrMissingParameter)
+	}
+}
+
+func TestClientEnsureUpsert(t *testing.T) {
+	ctx := context.Background()
+
+	c, calls, close := testRegistryServer(Upsert(true))
+	defer close()
+
+	series := MakeSeries("SC", "Series")
+
+	changed := series
+	changed.Title = "Changed"
+
+	if _, err := c.RegisterSeries(ctx, series); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		series Series
+		want   EnsureOutcome
+		calls  int
+	}{
+		{series, Updated, 3},
+		{series, Unchanged, 3},
+		{changed, Updated, 4},
+		{changed, Unchanged, 4},
+	} {
+		got, err := c.EnsureSeries(ctx, tt.series)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != tt.want {
+			t.Fatalf("outcome = %q, want %q", got, tt.want)
+		}
+
+		if got, want := calls(), tt.calls; got != want {
+			t.Fatalf("calls = %d, want %d", got, want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package titleservice
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Field length limits in the MMS TitleService API
+const (
+	maxPlayURLLength        = 150
+	maxSuggestedGenreLength = 256
+)
+
+// ValidationCode is a machine readable reason for a field failing validation
+type ValidationCode string
+
+// ValidationCodes
+const (
+	FieldMissing    ValidationCode = "missing"
+	FieldTooLong    ValidationCode = "too_long"
+	FieldBadFormat  ValidationCode = "bad_format"
+	FieldNotAllowed ValidationCode = "not_allowed_for_category"
+	FieldNotOnAir   ValidationCode = "not_on_air"
+)
+
+// FieldError describes why a single field failed validation
+type FieldError struct {
+	Field   string         // name of the field, e.g. "TitleCode"
+	Code    ValidationCode // reason for failing validation
+	Message string         // human readable message
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Unwrap returns ErrMissingParameter for missing fields, and ErrInvalidParameter otherwise
+func (e *FieldError) Unwrap() error {
+	if e.Code == FieldMissing {
+		return ErrMissingParameter
+	}
+
+	return ErrInvalidParameter
+}
+
+// ValidationError lists every field of a Series, Episode or Clip that failed validation
+type ValidationError struct {
+	Type   string // "Series", "Episode" or "Clip"
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+
+	for i, f := range e.Fields {
+		msgs[i] = e.Type + " " + f.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the FieldErrors, allowing errors.Is to match ErrMissingParameter and ErrInvalidParameter
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+
+	for i, f := range e.Fields {
+		errs[i] = f
+	}
+
+	return errs
+}
+
+// Field returns the FieldError for the named field, or nil if the field is valid
+func (e *ValidationError) Field(name string) *FieldError {
+	for _, f := range e.Fields {
+		if f.Field == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// add a FieldError with the default message for the code
+func (e *ValidationError) add(field string, code ValidationCode) {
+	e.addf(field, code, "")
+}
+
+// addf adds a FieldError with the default message for the code followed by detail
+func (e *ValidationError) addf(field string, code ValidationCode, detail string) {
+	msg := ErrInvalidParameter.Error()
+
+	if code == FieldMissing {
+		msg = ErrMissingParameter.Error()
+	}
+
+	if detail != "" {
+		msg += " (" + detail + ")"
+	}
+
+	e.Fields = append(e.Fields, &FieldError{Field: field, Code: code, Message: msg})
+}
+
+// err returns e if any field failed validation, and nil otherwise
+func (e *ValidationError) err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// maxLength adds a FieldError if value is longer than max characters
+func (e *ValidationError) maxLength(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		e.addf(field, FieldTooLong, fmt.Sprintf("longer than %d characters", max))
+	}
+}
+
+// validPlayURL returns true if s is an absolute http or https URL
+func validPlayURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,288 @@
+/*
+
+Package xmltv builds titleservice.Episode registrations for linear broadcasts from XMLTV schedules
+
+	schedule, err := xmltv.Parse(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	im := &xmltv.Importer{
+		Channels: map[string]titleservice.LiveChannelID{
+			"tv4.se": titleservice.TV4,
+		},
+		Match: func(p xmltv.Programme) (xmltv.Asset, bool) {
+			// look up the VOD asset of the programme
+		},
+	}
+
+	episodes, errs := im.Episodes(schedule)
+
+*/
+package xmltv
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TV4/mms/titleservice"
+)
+
+// Schedule is an XMLTV document
+type Schedule struct {
+	Channels   []Channel   `xml:"channel"`
+	Programmes []Programme `xml:"programme"`
+}
+
+// Channel in an XMLTV document
+type Channel struct {
+	ID           string   `xml:"id,attr"`
+	DisplayNames []string `xml:"display-name"`
+}
+
+// Programme in an XMLTV document
+type Programme struct {
+	Channel     string       `xml:"channel,attr"`
+	Start       Time         `xml:"start,attr"`
+	Stop        Time         `xml:"stop,attr"`
+	Title       string       `xml:"title"`
+	SubTitle    string       `xml:"sub-title"`
+	Description string       `xml:"desc"`
+	Categories  []string     `xml:"category"`
+	EpisodeNums []EpisodeNum `xml:"episode-num"`
+}
+
+// EpisodeNum of a programme, in the numbering system given by System
+type EpisodeNum struct {
+	System string `xml:"system,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// Time in the XMLTV format YYYYMMDDhhmmss +zzzz, where the seconds and
+// time zone offset are optional. Times without an offset are in Stockholm.
+type Time struct {
+	time.Time
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (t *Time) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	for _, layout := range []string{"20060102150405 -0700", "200601021504 -0700", "20060102150405", "200601021504"} {
+		if len(s) != len(layout) {
+			continue
+		}
+
+		parsed, err := time.ParseInLocation(layout, s, titleservice.Stockholm)
+		if err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+
+	return fmt.Errorf("xmltv: invalid time %q", s)
+}
+
+// Parse an XMLTV document
+func Parse(r io.Reader) (*Schedule, error) {
+	var s Schedule
+
+	if err := xml.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Length of the programme in seconds, or 0 if it has no stop time
+func (p Programme) Length() int {
+	if p.Stop.IsZero() || !p.Stop.After(p.Start.Time) {
+		return 0
+	}
+
+	return int(p.Stop.Sub(p.Start.Time) / time.Second)
+}
+
+// EpisodeNumber returns the episode number (starting at 1) from the xmltv_ns episode-num,
+// or 0 if there is none
+func (p Programme) EpisodeNumber() int {
+	for _, en := range p.EpisodeNums {
+		if en.System != "xmltv_ns" {
+			continue
+		}
+
+		parts := strings.Split(en.Value, ".")
+		if len(parts) < 2 {
+			continue
+		}
+
+		episode := strings.TrimSpace(strings.SplitN(parts[1], "/", 2)[0])
+
+		if n, err := strconv.Atoi(episode); err == nil && n >= 0 {
+			return n + 1
+		}
+	}
+
+	return 0
+}
+
+// Asset is the VOD asset matched to a programme. Fields left empty are taken from the programme.
+type Asset struct {
+	TitleCode   string                  // required
+	SeriesCode  string                  // required
+	Title       string                  // defaults to the programme title
+	Length      int                     // defaults to the programme length
+	PublishedAt titleservice.MMSDate    // defaults to the date the programme starts
+	CategoryID  titleservice.CategoryID // defaults to TvProgram
+	Options     []func(*titleservice.Episode)
+}
+
+// Importer builds Episodes from the programmes in a Schedule
+type Importer struct {
+	// Channels maps XMLTV channel ids to LiveChannelIDs. Channels not in
+	// the map are looked up by their display names using ResolveLiveChannelID.
+	Channels map[string]titleservice.LiveChannelID
+
+	// Match returns the VOD asset of a programme, and false for
+	// programmes that should not be registered (required)
+	Match func(Programme) (Asset, bool)
+}
+
+// ErrNoMatch is returned by Importer.Episodes if the Importer has no Match func
+var ErrNoMatch = errors.New("xmltv: Importer has no Match func")
+
+// ProgrammeError is an error building the Episode for a programme
+type ProgrammeError struct {
+	Programme Programme
+	Err       error
+}
+
+func (e *ProgrammeError) Error() string {
+	return fmt.Sprintf("xmltv: %s %q at %s: %v", e.Programme.Channel, e.Programme.Title, e.Programme.Start.Format(time.RFC3339), e.Err)
+}
+
+// Unwrap returns the error for the programme
+func (e *ProgrammeError) Unwrap() error {
+	return e.Err
+}
+
+// Episodes returns a validated Episode for every matched programme in the schedule,
+// and a *ProgrammeError for every matched programme that could not be built.
+// It returns ErrNoMatch if the Importer has no Match func.
+func (im *Importer) Episodes(s *Schedule) ([]titleservice.Episode, []error) {
+	if im.Match == nil {
+		return nil, []error{ErrNoMatch}
+	}
+
+	channels, unknown := im.channels(s)
+
+	var (
+		episodes []titleservice.Episode
+		errs     []error
+	)
+
+	for _, p := range s.Programmes {
+		asset, ok := im.Match(p)
+		if !ok {
+			continue
+		}
+
+		e, err := episode(p, asset, channels, unknown)
+		if err != nil {
+			errs = append(errs, &ProgrammeError{Programme: p, Err: err})
+			continue
+		}
+
+		episodes = append(episodes, e)
+	}
+
+	return episodes, errs
+}
+
+// channels returns the LiveChannelID of every channel id known, and the
+// error resolving the first display name of every other channel
+func (im *Importer) channels(s *Schedule) (map[string]titleservice.LiveChannelID, map[string]error) {
+	channels := map[string]titleservice.LiveChannelID{}
+	unknown := map[string]error{}
+
+	for _, c := range s.Channels {
+		for i, name := range c.DisplayNames {
+			id, err := titleservice.ResolveLiveChannelID(strings.TrimSpace(name))
+			if err == nil {
+				channels[c.ID] = id
+				break
+			}
+
+			if i == 0 {
+				unknown[c.ID] = err
+			}
+		}
+	}
+
+	for id, channelID := range im.Channels {
+		channels[id] = channelID
+	}
+
+	return channels, unknown
+}
+
+func episode(p Programme, a Asset, channels map[string]titleservice.LiveChannelID, unknown map[string]error) (titleservice.Episode, error) {
+	if p.Start.IsZero() {
+		return titleservice.Episode{}, fmt.Errorf("missing start time")
+	}
+
+	channel, ok := channels[p.Channel]
+	if !ok {
+		if err, ok := unknown[p.Channel]; ok {
+			return titleservice.Episode{}, fmt.Errorf("channel %q: %w", p.Channel, err)
+		}
+
+		return titleservice.Episode{}, fmt.Errorf("unknown channel %q", p.Channel)
+	}
+
+	title := a.Title
+	if title == "" {
+		title = p.Title
+	}
+
+	length := a.Length
+	if length == 0 {
+		length = p.Length()
+	}
+
+	publishedAt := a.PublishedAt
+	if publishedAt.IsZero() {
+		publishedAt = titleservice.DateAtTime(p.Start.Time)
+	}
+
+	categoryID := a.CategoryID
+	if categoryID == 0 {
+		categoryID = titleservice.TvProgram
+	}
+
+	options := []func(*titleservice.Episode){
+		titleservice.WithBroadcast(titleservice.Broadcast{Title: p.Title, Start: p.Start.Time, Channel: channel}),
+		titleservice.WithDescription(p.Description),
+	}
+
+	switch categoryID {
+	case titleservice.TvProgram, titleservice.TvSegment:
+		options = append(options, titleservice.WithEpisodeNumber(p.EpisodeNumber()))
+	}
+
+	e := titleservice.MakeEpisode(a.TitleCode, a.SeriesCode, title, length, publishedAt, categoryID,
+		append(options, a.Options...)...,
+	)
+
+	return e, e.Validate()
+}
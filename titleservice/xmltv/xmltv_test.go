@@ -0,0 +1,232 @@
+package xmltv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TV4/mms/titleservice"
+)
+
+const schedule = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE tv SYSTEM "xmltv.dtd">
+<tv generator-info-name="epg">
+  <channel id="tv4.se">
+    <display-name lang="sv">TV4</display-name>
+  </channel>
+  <channel id="sjuan.tv4.se">
+    <display-name lang="sv">Sjuan</display-name>
+  </channel>
+  <channel id="unknown.se">
+    <display-name lang="sv">Okänd</display-name>
+  </channel>
+  <programme start="20170325193000 +0100" stop="20170325200000 +0100" channel="tv4.se">
+    <title lang="sv">Nyheterna</title>
+    <desc lang="sv">Senaste nytt.</desc>
+    <episode-num system="xmltv_ns">2.11/20.</episode-num>
+  </programme>
+  <programme start="20170326010000 +0100" stop="20170326013000 +0100" channel="sjuan.tv4.se">
+    <title lang="sv">Nattfilm</title>
+  </programme>
+  <programme start="201703261200" stop="201703261300" channel="unknown.se">
+    <title lang="sv">Okänt</title>
+  </programme>
+  <programme start="20170326120000 +0200" stop="20170326130000 +0200" channel="tv4.se">
+    <title lang="sv">Ej matchad</title>
+  </programme>
+</tv>`
+
+func TestParse(t *testing.T) {
+	s, err := Parse(strings.NewReader(schedule))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := len(s.Channels), 3; got != want {
+		t.Fatalf("len(s.Channels) = %d, want %d", got, want)
+	}
+
+	if got, want := len(s.Programmes), 4; got != want {
+		t.Fatalf("len(s.Programmes) = %d, want %d", got, want)
+	}
+
+	p := s.Programmes[0]
+
+	if got, want := p.Channel, "tv4.se"; got != want {
+		t.Fatalf("p.Channel = %q, want %q", got, want)
+	}
+
+	if got, want := p.Title, "Nyheterna"; got != want {
+		t.Fatalf("p.Title = %q, want %q", got, want)
+	}
+
+	if got, want := p.Description, "Senaste nytt."; got != want {
+		t.Fatalf("p.Description = %q, want %q", got, want)
+	}
+
+	if got, want := p.Start.UTC(), time.Date(2017, time.March, 25, 18, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("p.Start = %v, want %v", got, want)
+	}
+
+	if got, want := p.Length(), 1800; got != want {
+		t.Fatalf("p.Length() = %d, want %d", got, want)
+	}
+
+	if got, want := p.EpisodeNumber(), 12; got != want {
+		t.Fatalf("p.EpisodeNumber() = %d, want %d", got, want)
+	}
+
+	if got, want := s.Programmes[2].Start.Location(), titleservice.Stockholm; got != want {
+		t.Fatalf("Start.Location() = %v, want %v", got, want)
+	}
+}
+
+func TestParseInvalidTime(t *testing.T) {
+	_, err := Parse(strings.NewReader(`<tv><programme start="2017-03-25" channel="tv4.se"></programme></tv>`))
+
+	if err == nil || !strings.Contains(err.Error(), `invalid time "2017-03-25"`) {
+		t.Fatalf("err = %v, want invalid time", err)
+	}
+}
+
+func TestProgrammeEpisodeNumber(t *testing.T) {
+	for _, tt := range []struct {
+		nums []EpisodeNum
+		want int
+	}{
+		{nil, 0},
+		{[]EpisodeNum{{"onscreen", "S01E05"}}, 0},
+		{[]EpisodeNum{{"xmltv_ns", "0.4."}}, 5},
+		{[]EpisodeNum{{"xmltv_ns", " . 0 . "}}, 1},
+		{[]EpisodeNum{{"xmltv_ns", "1.3/8.0/1"}}, 4},
+		{[]EpisodeNum{{"xmltv_ns", "1.."}}, 0},
+		{[]EpisodeNum{{"onscreen", "S01E05"}, {"xmltv_ns", "0.9."}}, 10},
+	} {
+		if got := (Programme{EpisodeNums: tt.nums}).EpisodeNumber(); got != tt.want {
+			t.Fatalf("EpisodeNumber() for %v = %d, want %d", tt.nums, got, tt.want)
+		}
+	}
+}
+
+func TestImporterEpisodes(t *testing.T) {
+	s, err := Parse(strings.NewReader(schedule))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	im := &Importer{
+		Channels: map[string]titleservice.LiveChannelID{
+			"sjuan.tv4.se": titleservice.Sjuan,
+		},
+		Match: func(p Programme) (Asset, bool) {
+			switch p.Title {
+			case "Nyheterna":
+				return Asset{TitleCode: "TC1", SeriesCode: "SC1"}, true
+			case "Nattfilm":
+				return Asset{
+					TitleCode:   "TC2",
+					SeriesCode:  "SC2",
+					Title:       "Nattfilmen",
+					Length:      1750,
+					PublishedAt: titleservice.Date(2017, time.March, 27),
+					CategoryID:  titleservice.Simulcast,
+					Options:     []func(*titleservice.Episode){titleservice.WithTerritory(titleservice.Foreign)},
+				}, true
+			case "Okänt":
+				return Asset{TitleCode: "TC3", SeriesCode: "SC3"}, true
+			}
+
+			return Asset{}, false
+		},
+	}
+
+	episodes, errs := im.Episodes(s)
+
+	if got, want := len(episodes), 2; got != want {
+		t.Fatalf("len(episodes) = %d, want %d", got, want)
+	}
+
+	for _, tt := range []struct {
+		got  titleservice.Episode
+		want titleservice.Episode
+	}{
+		{
+			episodes[0],
+			titleservice.MakeEpisode("TC1", "SC1", "Nyheterna", 1800, titleservice.Date(2017, time.March, 25), titleservice.TvProgram,
+				titleservice.WithLive("Nyheterna", titleservice.Date(2017, time.March, 25), titleservice.BroadcastTime(19, 30), titleservice.TV4),
+				titleservice.WithDescription("Senaste nytt."),
+				titleservice.WithEpisodeNumber(12),
+			),
+		},
+		{
+			episodes[1],
+			titleservice.MakeEpisode("TC2", "SC2", "Nattfilmen", 1750, titleservice.Date(2017, time.March, 27), titleservice.Simulcast,
+				titleservice.WithLive("Nattfilm", titleservice.Date(2017, time.March, 25), titleservice.BroadcastTime(1, 0), titleservice.Sjuan),
+				titleservice.WithTerritory(titleservice.Foreign),
+			),
+		},
+	} {
+		if tt.got != tt.want {
+			t.Fatalf("episode = %+v, want %+v", tt.got, tt.want)
+		}
+	}
+
+	if got, want := len(errs), 1; got != want {
+		t.Fatalf("len(errs) = %d, want %d", got, want)
+	}
+
+	var pe *ProgrammeError
+
+	if !errors.As(errs[0], &pe) {
+		t.Fatalf("errs[0] = %T, want *ProgrammeError", errs[0])
+	}
+
+	if got, want := pe.Programme.Title, "Okänt"; got != want {
+		t.Fatalf("pe.Programme.Title = %q, want %q", got, want)
+	}
+
+	if got, want := errs[0].Error(), `xmltv: unknown.se "Okänt" at 2017-03-26T12:00:00+02:00: channel "unknown.se": unknown channel "Okänd"`; got != want {
+		t.Fatalf("errs[0].Error() = %q, want %q", got, want)
+	}
+}
+
+func TestImporterEpisodesValidation(t *testing.T) {
+	s, err := Parse(strings.NewReader(schedule))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	im := &Importer{
+		Match: func(p Programme) (Asset, bool) {
+			return Asset{SeriesCode: "SC1"}, p.Title == "Nyheterna"
+		},
+	}
+
+	episodes, errs := im.Episodes(s)
+
+	if got, want := len(episodes), 0; got != want {
+		t.Fatalf("len(episodes) = %d, want %d", got, want)
+	}
+
+	if got, want := len(errs), 1; got != want {
+		t.Fatalf("len(errs) = %d, want %d", got, want)
+	}
+
+	if !errors.Is(errs[0], titleservice.ErrMissingParameter) {
+		t.Fatalf("errs[0] = %v, want ErrMissingParameter", errs[0])
+	}
+}
+
+func TestImporterEpisodesNoMatch(t *testing.T) {
+	s, err := Parse(strings.NewReader(schedule))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	episodes, errs := (&Importer{}).Episodes(s)
+
+	if len(episodes) != 0 || len(errs) != 1 || !errors.Is(errs[0], ErrNoMatch) {
+		t.Fatalf("Episodes = %v, %v, want no episodes and ErrNoMatch", episodes, errs)
+	}
+}